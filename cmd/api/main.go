@@ -1,12 +1,18 @@
 package main
 
 import (
-	"log"
+	"context"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/gkatanacio/marvel-characters-api/internal/handlers"
+	"github.com/gkatanacio/marvel-characters-api/internal/logging"
 	"github.com/gkatanacio/marvel-characters-api/internal/marvel"
 	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus"
 )
 
 // @title Marvel Characters API
@@ -16,29 +22,156 @@ import (
 // @license.url http://www.apache.org/licenses/LICENSE-2.0.html
 // @host localhost:8080
 func main() {
-	log.Println("server startup")
-
 	cfg := marvel.NewConfig()
-	client := marvel.NewClient(cfg)
+	logLevel, _ := logging.ParseLevel(cfg.LogLevel) // already validated
+	logger := logging.New(os.Stderr, logLevel)
+
+	logger.Info("server startup")
+
+	client := marvel.NewClient(cfg, logger.With("component", "client"))
 	cache := marvel.NewInMemCache()
-	service := marvel.NewService(client, cache)
+	detailCache := marvel.NewInMemDetailCache(cfg.DetailCache)
+	service := marvel.NewService(cfg, client, cache, detailCache, logger.With("component", "service"))
 
-	if cfg.EagerLoadCache {
-		log.Println("prepopulating cache")
-		if err := service.ReloadCache(); err != nil {
-			log.Println(err)
+	restored := false
+	if cfg.SnapshotPath != "" {
+		if err := service.RestoreSnapshot(); err != nil {
+			logger.Warn("cache snapshot not restored", "error", err)
+		} else {
+			restored = true
+		}
+	}
+
+	if restored {
+		logger.Info("cache restored from snapshot, syncing changes")
+		if err := service.SyncCache(context.Background()); err != nil {
+			logger.Error("cache sync failed", "error", err)
+		}
+	} else if cfg.EagerLoadCache {
+		logger.Info("prepopulating cache")
+		if err := service.ReloadCache(context.Background()); err != nil {
+			logger.Error("cache prepopulation failed", "error", err)
 			panic("failed to populate cache")
 		}
 	}
 
-	getAllCharactersHandler := handlers.NewGetAllCharactersHandler(service)
-	getCharacterInfoHandler := handlers.NewGetCharacterInfoHandler(service)
+	handlerLogger := logger.With("component", "handlers")
+
+	getAllCharactersHandler := handlers.NewGetAllCharactersHandler(service, handlerLogger)
+	getChangesHandler := handlers.NewGetChangesHandler(service, handlerLogger)
+	streamChangesHandler := handlers.NewStreamChangesHandler(service, cfg.Stream.HeartbeatInterval, handlerLogger)
+	getCharacterInfoHandler := handlers.NewGetCharacterInfoHandler(service, handlerLogger)
+	getCharacterComicsHandler := handlers.NewGetCharacterComicsHandler(service, handlerLogger)
+	getCharacterSeriesHandler := handlers.NewGetCharacterSeriesHandler(service, handlerLogger)
+	getCharacterEventsHandler := handlers.NewGetCharacterEventsHandler(service, handlerLogger)
+	getCharacterStoriesHandler := handlers.NewGetCharacterStoriesHandler(service, handlerLogger)
+	getConnectionsHandler := handlers.NewGetConnectionsHandler(service, handlerLogger)
+	getPathHandler := handlers.NewGetPathHandler(service, handlerLogger)
+	searchHandler := handlers.NewSearchHandler(service, handlerLogger)
+	getComicsHandler := handlers.NewGetComicsHandler(service, handlerLogger)
+	getComicHandler := handlers.NewGetComicHandler(service, handlerLogger)
+	getSeriesListHandler := handlers.NewGetSeriesListHandler(service, handlerLogger)
+	getSeriesHandler := handlers.NewGetSeriesHandler(service, handlerLogger)
+	getEventsHandler := handlers.NewGetEventsHandler(service, handlerLogger)
+	getEventHandler := handlers.NewGetEventHandler(service, handlerLogger)
+	getStoriesHandler := handlers.NewGetStoriesHandler(service, handlerLogger)
+	getStoryHandler := handlers.NewGetStoryHandler(service, handlerLogger)
+	getCreatorsHandler := handlers.NewGetCreatorsHandler(service, handlerLogger)
+	getCreatorHandler := handlers.NewGetCreatorHandler(service, handlerLogger)
+	webhooks := marvel.NewWebhookDispatcher(cfg.Webhooks, logger.With("component", "webhooks"))
+	webhooks.Start(service)
+
+	getBudgetHandler := handlers.NewGetBudgetHandler(client.Budget())
+	getSyncStatusHandler := handlers.NewGetSyncStatusHandler(service)
+
+	service.RegisterMetrics(prometheus.DefaultRegisterer)
+	client.Budget().RegisterMetrics(prometheus.DefaultRegisterer)
+	getMetricsHandler := handlers.NewGetMetricsHandler(prometheus.DefaultGatherer)
 
 	r := mux.NewRouter()
+	r.Use(handlers.Instrument)
 	r.HandleFunc("/characters", getAllCharactersHandler.Handle).Methods(http.MethodGet)
+	r.HandleFunc("/characters/changes", getChangesHandler.Handle).Methods(http.MethodGet)
+	r.HandleFunc("/characters/stream", streamChangesHandler.Handle).Methods(http.MethodGet)
 	r.HandleFunc("/characters/{id}", getCharacterInfoHandler.Handle).Methods(http.MethodGet)
+	r.HandleFunc("/characters/{id}/comics", getCharacterComicsHandler.Handle).Methods(http.MethodGet)
+	r.HandleFunc("/characters/{id}/series", getCharacterSeriesHandler.Handle).Methods(http.MethodGet)
+	r.HandleFunc("/characters/{id}/events", getCharacterEventsHandler.Handle).Methods(http.MethodGet)
+	r.HandleFunc("/characters/{id}/stories", getCharacterStoriesHandler.Handle).Methods(http.MethodGet)
+	r.HandleFunc("/characters/{id}/connections", getConnectionsHandler.Handle).Methods(http.MethodGet)
+	r.HandleFunc("/characters/{id}/path/{toId}", getPathHandler.Handle).Methods(http.MethodGet)
+	r.HandleFunc("/search", searchHandler.Handle).Methods(http.MethodGet)
+	r.HandleFunc("/comics", getComicsHandler.Handle).Methods(http.MethodGet)
+	r.HandleFunc("/comics/{id}", getComicHandler.Handle).Methods(http.MethodGet)
+	r.HandleFunc("/series", getSeriesListHandler.Handle).Methods(http.MethodGet)
+	r.HandleFunc("/series/{id}", getSeriesHandler.Handle).Methods(http.MethodGet)
+	r.HandleFunc("/events", getEventsHandler.Handle).Methods(http.MethodGet)
+	r.HandleFunc("/events/{id}", getEventHandler.Handle).Methods(http.MethodGet)
+	r.HandleFunc("/stories", getStoriesHandler.Handle).Methods(http.MethodGet)
+	r.HandleFunc("/stories/{id}", getStoryHandler.Handle).Methods(http.MethodGet)
+	r.HandleFunc("/creators", getCreatorsHandler.Handle).Methods(http.MethodGet)
+	r.HandleFunc("/creators/{id}", getCreatorHandler.Handle).Methods(http.MethodGet)
+	r.HandleFunc("/budget", getBudgetHandler.Handle).Methods(http.MethodGet)
+	r.HandleFunc("/sync", getSyncStatusHandler.Handle).Methods(http.MethodGet)
+	r.HandleFunc("/metrics", getMetricsHandler.Handle).Methods(http.MethodGet)
+
+	if token := cfg.Webhooks.AdminToken; token != "" {
+		getWebhooksHandler := handlers.NewGetWebhooksHandler(webhooks)
+		registerWebhookHandler := handlers.NewRegisterWebhookHandler(webhooks, handlerLogger)
+		unregisterWebhookHandler := handlers.NewUnregisterWebhookHandler(webhooks)
+		getWebhookDeliveriesHandler := handlers.NewGetWebhookDeliveriesHandler(webhooks)
+		getDeadLettersHandler := handlers.NewGetDeadLettersHandler(webhooks)
+		retryDeadLetterHandler := handlers.NewRetryDeadLetterHandler(webhooks)
+
+		r.HandleFunc("/admin/webhooks", handlers.RequireToken(token, getWebhooksHandler.Handle)).Methods(http.MethodGet)
+		r.HandleFunc("/admin/webhooks", handlers.RequireToken(token, registerWebhookHandler.Handle)).Methods(http.MethodPost)
+		r.HandleFunc("/admin/webhooks/dead-letters", handlers.RequireToken(token, getDeadLettersHandler.Handle)).Methods(http.MethodGet)
+		r.HandleFunc("/admin/webhooks/dead-letters/{id}/retry", handlers.RequireToken(token, retryDeadLetterHandler.Handle)).Methods(http.MethodPost)
+		r.HandleFunc("/admin/webhooks/{id}", handlers.RequireToken(token, unregisterWebhookHandler.Handle)).Methods(http.MethodDelete)
+		r.HandleFunc("/admin/webhooks/{id}/deliveries", handlers.RequireToken(token, getWebhookDeliveriesHandler.Handle)).Methods(http.MethodGet)
+	}
+
+	var refresher *marvel.Refresher
+	if cfg.Refresh.Interval > 0 {
+		logger.Info("syncing cache periodically", "interval", cfg.Refresh.Interval)
+		refresher = marvel.NewRefresher(service, cfg.Refresh, logger.With("component", "refresher"))
+		refresher.Start()
+	}
 
 	port := ":8080"
-	log.Printf("listening on port %s", port)
-	log.Fatal(http.ListenAndServe(port, r))
+	srv := &http.Server{Addr: port, Handler: handlers.LogRequests(handlerLogger)(r)}
+	// open streams would otherwise hold up the shutdown
+	srv.RegisterOnShutdown(service.CloseChangeSubscriptions)
+
+	go func() {
+		logger.Info("listening", "port", port)
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			logger.Error("server failed", "error", err)
+			os.Exit(1)
+		}
+	}()
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+	<-stop
+
+	logger.Info("server shutdown")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(ctx); err != nil {
+		logger.Error("server shutdown failed", "error", err)
+	}
+
+	if refresher != nil {
+		refresher.Stop()
+	}
+
+	webhooks.Stop()
+
+	if cfg.SnapshotPath != "" {
+		if err := service.SaveSnapshot(); err != nil {
+			logger.Error("failed to save cache snapshot", "error", err)
+		}
+	}
 }
@@ -28,8 +28,228 @@ var doc = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/admin/webhooks": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Get the registered webhooks",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer admin token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/marvel.Webhook"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "The webhook is sent a payload describing the changes found by each sync with Marvel's API from now on.\nPayloads are signed with the secret of the webhook (only returned here): the X-Webhook-Signature header\nholds sha256= followed by the hex-encoded HMAC-SHA256 of the X-Webhook-Timestamp header, a dot and the body.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Register a webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer admin token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Webhook to register (a random secret is generated if none is given)",
+                        "name": "webhook",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/marvel.WebhookRegistration"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/marvel.Webhook"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/webhooks/dead-letters": {
+            "get": {
+                "description": "Deliveries whose attempts all failed are listed from the newest to the oldest.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Get the most recent failed webhook deliveries",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer admin token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/marvel.WebhookDelivery"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/webhooks/dead-letters/{id}/retry": {
+            "post": {
+                "description": "The delivery is taken off the dead-letter list and attempted again in the background.",
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Retry a failed webhook delivery",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer admin token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Delivery ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted"
+                    }
+                }
+            }
+        },
+        "/admin/webhooks/{id}": {
+            "delete": {
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Unregister a webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer admin token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/admin/webhooks/{id}/deliveries": {
+            "get": {
+                "description": "Deliveries are listed from the newest to the oldest, each with its attempts.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Get the most recent deliveries to a webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer admin token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/marvel.WebhookDelivery"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/budget": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Status"
+                ],
+                "summary": "Get the remaining Marvel API call budget",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/marvel.BudgetStatus"
+                        }
+                    }
+                }
+            }
+        },
         "/characters": {
             "get": {
+                "description": "Character IDs are returned as a bare array, unless limit or offset is given,\nin which case a page of them is returned in an envelope with links to the adjacent pages.\nWhen searching by name or nameStartsWith, a page of the matching characters\n(sorted by name) is returned in the envelope instead.\nWhen looking up ids, the characters found are returned (in the order of the ids),\nalong with an error for each ID that could not be looked up.",
                 "produces": [
                     "application/json"
                 ],
@@ -37,6 +257,56 @@ var doc = `{
                     "Characters"
                 ],
                 "summary": "Get all Character IDs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Comma-separated IDs of the characters to look up (up to 100)",
+                        "name": "ids",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of the characters to search for (case-insensitive)",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Beginning of the name of the characters to search for (case-insensitive)",
+                        "name": "nameStartsWith",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of IDs to return (1 to 100, default 20, not allowed with ids)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of IDs to skip (not allowed with ids)",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Order of the IDs (id, name or modified, prefixed with - for descending order, id by default)",
+                        "name": "orderBy",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields of the characters looked up or searched by name to include (id, name, description, modified, resourceURI, thumbnail, urls, all by default)",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated related resource summaries of the characters looked up or searched by name to include (comics, series, stories, events)",
+                        "name": "expand",
+                        "in": "query"
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -45,6 +315,88 @@ var doc = `{
                             "items": {
                                 "type": "integer"
                             }
+                        },
+                        "headers": {
+                            "Age": {
+                                "type": "integer",
+                                "description": "Seconds since the last successful sync with Marvel's API"
+                            },
+                            "X-Cache-Status": {
+                                "type": "string",
+                                "description": "stale if the last sync with Marvel's API failed, fresh otherwise"
+                            },
+                            "X-Last-Synced": {
+                                "type": "string",
+                                "description": "Time of the last successful sync with Marvel's API (RFC 3339)"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/characters/changes": {
+            "get": {
+                "description": "Changes are found by the syncs with Marvel's API and listed in the order they were found.\nEach response comes with a cursor to resume from, to get the changes that follow.\nChanges made too long ago to still be available result in a 410 error,\nafter which the full list of characters should be fetched again.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Characters"
+                ],
+                "summary": "Get the Characters added, modified and removed since a given time",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Time since which to list changes (RFC 3339), required unless cursor is given",
+                        "name": "since",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor returned by a previous request, to list the changes that followed",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of changes to return (1 to 100, default 100)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/marvel.ChangePage"
+                        }
+                    }
+                }
+            }
+        },
+        "/characters/stream": {
+            "get": {
+                "description": "A Server-Sent Events stream with an event for each character added, modified or removed\nby the syncs with Marvel's API (the data of each event is a change, as listed by /characters/changes).\nThe ID of each event can be sent back in the Last-Event-ID header when reconnecting,\nto get the changes missed in between. Streams falling too far behind are closed.",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "Characters"
+                ],
+                "summary": "Stream the changes to the Characters as they are found",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID of the last event received, to resume from",
+                        "name": "Last-Event-ID",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/marvel.Change"
                         }
                     }
                 }
@@ -66,6 +418,24 @@ var doc = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields to include (id, name, description, modified, resourceURI, thumbnail, urls, all by default)",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated related resource summaries to include (comics, series, stories, events)",
+                        "name": "expand",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Variant of the thumbnail URL (e.g. portrait_xlarge or standard_fantastic, full size by default)",
+                        "name": "imageVariant",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -77,19 +447,1377 @@ var doc = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "marvel.Character": {
-            "type": "object",
-            "properties": {
-                "description": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "integer"
-                },
-                "name": {
+        },
+        "/characters/{id}/comics": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Characters"
+                ],
+                "summary": "Get a page of the Comics a Character appears in",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Character ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of comics to return (1 to 100, default 20)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of comics to skip",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Order of the comics (focDate, onsaleDate, title, issueNumber, modified, optionally prefixed with '-' for descending order)",
+                        "name": "orderBy",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Format of the comics (comic, magazine, trade paperback, hardcover, digest, graphic novel, digital comic, infinite comic)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Range of release dates of the comics (YYYY-MM-DD,YYYY-MM-DD)",
+                        "name": "dateRange",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/marvel.Comic"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/characters/{id}/connections": {
+            "get": {
+                "description": "A page of the characters sharing comics with the character is returned in an envelope,\nfrom the one sharing the most comics to the one sharing the least.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Characters"
+                ],
+                "summary": "Get the Characters appearing in comics together with a Character",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Character ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of characters to return (1 to 100, default 20)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of characters to skip",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/marvel.Connection"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/characters/{id}/events": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Characters"
+                ],
+                "summary": "Get a page of the Events a Character appears in",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Character ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of events to return (1 to 100, default 20)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of events to skip",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Order of the events (name, startDate, modified, optionally prefixed with '-' for descending order)",
+                        "name": "orderBy",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/marvel.Event"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/characters/{id}/path/{toId}": {
+            "get": {
+                "description": "The chain includes both characters. Each character after the first comes with\nthe number of comics shared with the previous one.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Characters"
+                ],
+                "summary": "Get the shortest chain of Characters linking two Characters through shared comics",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Character ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID of the Character to link to",
+                        "name": "toId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/marvel.Connection"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/characters/{id}/series": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Characters"
+                ],
+                "summary": "Get a page of the Series a Character appears in",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Character ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of series to return (1 to 100, default 20)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of series to skip",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Order of the series (title, modified, startYear, optionally prefixed with '-' for descending order)",
+                        "name": "orderBy",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/marvel.Series"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/characters/{id}/stories": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Characters"
+                ],
+                "summary": "Get a page of the Stories a Character appears in",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Character ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of stories to return (1 to 100, default 20)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of stories to skip",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Order of the stories (id, modified, optionally prefixed with '-' for descending order)",
+                        "name": "orderBy",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/marvel.Story"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/comics": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Comics"
+                ],
+                "summary": "Get a page of Comics",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of comics to return (1 to 100, default 20)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of comics to skip",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/marvel.Comic"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/comics/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Comics"
+                ],
+                "summary": "Get Comic information",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Comic ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/marvel.Comic"
+                        }
+                    }
+                }
+            }
+        },
+        "/creators": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Creators"
+                ],
+                "summary": "Get a page of Creators",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of creators to return (1 to 100, default 20)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of creators to skip",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/marvel.Creator"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/creators/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Creators"
+                ],
+                "summary": "Get Creator information",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Creator ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/marvel.Creator"
+                        }
+                    }
+                }
+            }
+        },
+        "/events": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Events"
+                ],
+                "summary": "Get a page of Events",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of events to return (1 to 100, default 20)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of events to skip",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/marvel.Event"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/events/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Events"
+                ],
+                "summary": "Get Event information",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/marvel.Event"
+                        }
+                    }
+                }
+            }
+        },
+        "/metrics": {
+            "get": {
+                "description": "Covers the requests served, the calls made to Marvel's API, the caches and their syncs, and the call budget.",
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "Status"
+                ],
+                "summary": "Get the metrics of the gateway in the Prometheus text format",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/search": {
+            "get": {
+                "description": "Characters are matched by name (tolerating typos and missing spaces or hyphens)\nand by the words of their descriptions. A page of them is returned in an envelope,\nranked by relevance, each with its score.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Characters"
+                ],
+                "summary": "Search characters",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Search query",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of characters to return (1 to 100, default 20)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of characters to skip",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields to include (id, name, description, modified, resourceURI, thumbnail, urls, score, all by default)",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated related resource summaries to include (comics, series, stories, events)",
+                        "name": "expand",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/marvel.SearchResult"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/series": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Series"
+                ],
+                "summary": "Get a page of Series",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of series to return (1 to 100, default 20)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of series to skip",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/marvel.Series"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/series/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Series"
+                ],
+                "summary": "Get Series information",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Series ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/marvel.Series"
+                        }
+                    }
+                }
+            }
+        },
+        "/stories": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Stories"
+                ],
+                "summary": "Get a page of Stories",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of stories to return (1 to 100, default 20)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of stories to skip",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/marvel.Story"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/stories/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Stories"
+                ],
+                "summary": "Get Story information",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Story ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/marvel.Story"
+                        }
+                    }
+                }
+            }
+        },
+        "/sync": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Status"
+                ],
+                "summary": "Get the status of the cache syncs with Marvel's API",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/marvel.SyncStatus"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "marvel.BudgetStatus": {
+            "type": "object",
+            "properties": {
+                "limit": {
+                    "type": "integer"
+                },
+                "remaining": {
+                    "type": "integer"
+                },
+                "reserve": {
+                    "type": "integer"
+                },
+                "resetsAt": {
+                    "type": "string"
+                },
+                "used": {
+                    "type": "integer"
+                }
+            }
+        },
+        "marvel.Change": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "modified": {
+                    "description": "Modified is when the character was last modified according to Marvel's API (unset for removals)",
+                    "type": "string"
+                },
+                "time": {
+                    "description": "Time is when the change was found by a sync",
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "marvel.ChangePage": {
+            "type": "object",
+            "properties": {
+                "changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/marvel.Change"
+                    }
+                },
+                "cursor": {
+                    "type": "string"
+                },
+                "hasMore": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "marvel.Character": {
+            "type": "object",
+            "properties": {
+                "comics": {
+                    "$ref": "#/definitions/marvel.ResourceList"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "events": {
+                    "$ref": "#/definitions/marvel.ResourceList"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "modified": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "resourceURI": {
+                    "type": "string"
+                },
+                "series": {
+                    "$ref": "#/definitions/marvel.ResourceList"
+                },
+                "stories": {
+                    "$ref": "#/definitions/marvel.ResourceList"
+                },
+                "thumbnail": {
+                    "$ref": "#/definitions/marvel.Thumbnail"
+                },
+                "urls": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/marvel.Link"
+                    }
+                }
+            }
+        },
+        "marvel.Comic": {
+            "type": "object",
+            "properties": {
+                "characters": {
+                    "$ref": "#/definitions/marvel.ResourceList"
+                },
+                "creators": {
+                    "$ref": "#/definitions/marvel.ResourceList"
+                },
+                "dates": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/marvel.ComicDate"
+                    }
+                },
+                "description": {
+                    "type": "string"
+                },
+                "digitalId": {
+                    "type": "integer"
+                },
+                "events": {
+                    "$ref": "#/definitions/marvel.ResourceList"
+                },
+                "format": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "isbn": {
+                    "type": "string"
+                },
+                "issueNumber": {
+                    "type": "number"
+                },
+                "modified": {
+                    "type": "string"
+                },
+                "pageCount": {
+                    "type": "integer"
+                },
+                "prices": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/marvel.Price"
+                    }
+                },
+                "resourceURI": {
+                    "type": "string"
+                },
+                "series": {
+                    "$ref": "#/definitions/marvel.ResourceSummary"
+                },
+                "stories": {
+                    "$ref": "#/definitions/marvel.ResourceList"
+                },
+                "thumbnail": {
+                    "$ref": "#/definitions/marvel.Thumbnail"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "upc": {
+                    "type": "string"
+                },
+                "urls": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/marvel.Link"
+                    }
+                },
+                "variantDescription": {
+                    "type": "string"
+                }
+            }
+        },
+        "marvel.ComicDate": {
+            "type": "object",
+            "properties": {
+                "date": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "marvel.Connection": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "sharedComics": {
+                    "description": "SharedComics is the number of comics the two characters appear in together",
+                    "type": "integer"
+                }
+            }
+        },
+        "marvel.Creator": {
+            "type": "object",
+            "properties": {
+                "comics": {
+                    "$ref": "#/definitions/marvel.ResourceList"
+                },
+                "events": {
+                    "$ref": "#/definitions/marvel.ResourceList"
+                },
+                "firstName": {
+                    "type": "string"
+                },
+                "fullName": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "lastName": {
+                    "type": "string"
+                },
+                "middleName": {
+                    "type": "string"
+                },
+                "modified": {
+                    "type": "string"
+                },
+                "resourceURI": {
+                    "type": "string"
+                },
+                "series": {
+                    "$ref": "#/definitions/marvel.ResourceList"
+                },
+                "stories": {
+                    "$ref": "#/definitions/marvel.ResourceList"
+                },
+                "suffix": {
+                    "type": "string"
+                },
+                "thumbnail": {
+                    "$ref": "#/definitions/marvel.Thumbnail"
+                },
+                "urls": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/marvel.Link"
+                    }
+                }
+            }
+        },
+        "marvel.DeliveryAttempt": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "statusCode": {
+                    "description": "StatusCode is the status of the response of the webhook (unset if no response was received)",
+                    "type": "integer"
+                },
+                "time": {
+                    "type": "string"
+                }
+            }
+        },
+        "marvel.Event": {
+            "type": "object",
+            "properties": {
+                "characters": {
+                    "$ref": "#/definitions/marvel.ResourceList"
+                },
+                "comics": {
+                    "$ref": "#/definitions/marvel.ResourceList"
+                },
+                "creators": {
+                    "$ref": "#/definitions/marvel.ResourceList"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "end": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "modified": {
+                    "type": "string"
+                },
+                "next": {
+                    "$ref": "#/definitions/marvel.ResourceSummary"
+                },
+                "previous": {
+                    "$ref": "#/definitions/marvel.ResourceSummary"
+                },
+                "resourceURI": {
+                    "type": "string"
+                },
+                "series": {
+                    "$ref": "#/definitions/marvel.ResourceList"
+                },
+                "start": {
+                    "type": "string"
+                },
+                "stories": {
+                    "$ref": "#/definitions/marvel.ResourceList"
+                },
+                "thumbnail": {
+                    "$ref": "#/definitions/marvel.Thumbnail"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "urls": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/marvel.Link"
+                    }
+                }
+            }
+        },
+        "marvel.Link": {
+            "type": "object",
+            "properties": {
+                "type": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "marvel.Price": {
+            "type": "object",
+            "properties": {
+                "price": {
+                    "type": "number"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "marvel.ReconcileResult": {
+            "type": "object",
+            "properties": {
+                "added": {
+                    "type": "integer"
+                },
+                "removed": {
+                    "type": "integer"
+                },
+                "skipped": {
+                    "description": "Skipped is true if the cache was left untouched because the complete list\nlooked suspiciously short (e.g. due to a partial failure of Marvel's API).",
+                    "type": "boolean"
+                },
+                "time": {
+                    "type": "string"
+                }
+            }
+        },
+        "marvel.ResourceList": {
+            "type": "object",
+            "properties": {
+                "available": {
+                    "type": "integer"
+                },
+                "collectionURI": {
+                    "type": "string"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/marvel.ResourceSummary"
+                    }
+                },
+                "returned": {
+                    "type": "integer"
+                }
+            }
+        },
+        "marvel.ResourceSummary": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "resourceURI": {
+                    "type": "string"
+                },
+                "role": {
+                    "description": "Role is only set for creators (e.g. \"writer\" or \"penciller\")",
+                    "type": "string"
+                },
+                "type": {
+                    "description": "Type is only set for stories (e.g. \"cover\" or \"interiorStory\")",
+                    "type": "string"
+                }
+            }
+        },
+        "marvel.SearchResult": {
+            "type": "object",
+            "properties": {
+                "comics": {
+                    "$ref": "#/definitions/marvel.ResourceList"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "events": {
+                    "$ref": "#/definitions/marvel.ResourceList"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "modified": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "resourceURI": {
+                    "type": "string"
+                },
+                "score": {
+                    "type": "number"
+                },
+                "series": {
+                    "$ref": "#/definitions/marvel.ResourceList"
+                },
+                "stories": {
+                    "$ref": "#/definitions/marvel.ResourceList"
+                },
+                "thumbnail": {
+                    "$ref": "#/definitions/marvel.Thumbnail"
+                },
+                "urls": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/marvel.Link"
+                    }
+                }
+            }
+        },
+        "marvel.Series": {
+            "type": "object",
+            "properties": {
+                "characters": {
+                    "$ref": "#/definitions/marvel.ResourceList"
+                },
+                "comics": {
+                    "$ref": "#/definitions/marvel.ResourceList"
+                },
+                "creators": {
+                    "$ref": "#/definitions/marvel.ResourceList"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "endYear": {
+                    "type": "integer"
+                },
+                "events": {
+                    "$ref": "#/definitions/marvel.ResourceList"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "modified": {
+                    "type": "string"
+                },
+                "next": {
+                    "$ref": "#/definitions/marvel.ResourceSummary"
+                },
+                "previous": {
+                    "$ref": "#/definitions/marvel.ResourceSummary"
+                },
+                "rating": {
+                    "type": "string"
+                },
+                "resourceURI": {
+                    "type": "string"
+                },
+                "startYear": {
+                    "type": "integer"
+                },
+                "stories": {
+                    "$ref": "#/definitions/marvel.ResourceList"
+                },
+                "thumbnail": {
+                    "$ref": "#/definitions/marvel.Thumbnail"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "urls": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/marvel.Link"
+                    }
+                }
+            }
+        },
+        "marvel.Story": {
+            "type": "object",
+            "properties": {
+                "characters": {
+                    "$ref": "#/definitions/marvel.ResourceList"
+                },
+                "comics": {
+                    "$ref": "#/definitions/marvel.ResourceList"
+                },
+                "creators": {
+                    "$ref": "#/definitions/marvel.ResourceList"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "events": {
+                    "$ref": "#/definitions/marvel.ResourceList"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "modified": {
+                    "type": "string"
+                },
+                "originalIssue": {
+                    "$ref": "#/definitions/marvel.ResourceSummary"
+                },
+                "resourceURI": {
+                    "type": "string"
+                },
+                "series": {
+                    "$ref": "#/definitions/marvel.ResourceList"
+                },
+                "thumbnail": {
+                    "$ref": "#/definitions/marvel.Thumbnail"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "marvel.SyncStatus": {
+            "type": "object",
+            "properties": {
+                "consecutiveFailures": {
+                    "type": "integer"
+                },
+                "failedSyncs": {
+                    "type": "integer"
+                },
+                "lastAttempt": {
+                    "type": "string"
+                },
+                "lastError": {
+                    "type": "string"
+                },
+                "lastReconcile": {
+                    "$ref": "#/definitions/marvel.ReconcileResult"
+                },
+                "lastSuccess": {
+                    "type": "string"
+                }
+            }
+        },
+        "marvel.Thumbnail": {
+            "type": "object",
+            "properties": {
+                "extension": {
+                    "type": "string"
+                },
+                "path": {
+                    "type": "string"
+                },
+                "url": {
+                    "description": "Url is the ready-to-use URL of the image, full size unless another variant was requested",
+                    "type": "string"
+                }
+            }
+        },
+        "marvel.Webhook": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "fromConfig": {
+                    "description": "FromConfig is true for the webhooks configured through environment variables, which cannot be unregistered",
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "secret": {
+                    "description": "Secret is only returned when the webhook is registered",
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "marvel.WebhookDelivery": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/marvel.DeliveryAttempt"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "payload": {
+                    "$ref": "#/definitions/marvel.WebhookPayload"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                },
+                "webhookId": {
+                    "type": "string"
+                }
+            }
+        },
+        "marvel.WebhookPayload": {
+            "type": "object",
+            "properties": {
+                "changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/marvel.Change"
+                    }
+                },
+                "event": {
+                    "type": "string"
+                },
+                "syncedAt": {
+                    "description": "SyncedAt is when the sync that found the changes was made",
+                    "type": "string"
+                }
+            }
+        },
+        "marvel.WebhookRegistration": {
+            "type": "object",
+            "properties": {
+                "secret": {
+                    "description": "Secret is the key the payloads are signed with. A random one is generated if empty.",
+                    "type": "string"
+                },
+                "url": {
                     "type": "string"
                 }
             }
@@ -46,3 +46,39 @@ func NewBadGateway(err string) *BadGateway {
 func (e *BadGateway) StatusCode() int {
 	return http.StatusBadGateway
 }
+
+type ServiceUnavailable struct {
+	error
+}
+
+func NewServiceUnavailable(err string) *ServiceUnavailable {
+	return &ServiceUnavailable{errors.New(err)}
+}
+
+func (e *ServiceUnavailable) StatusCode() int {
+	return http.StatusServiceUnavailable
+}
+
+type Gone struct {
+	error
+}
+
+func NewGone(err string) *Gone {
+	return &Gone{errors.New(err)}
+}
+
+func (e *Gone) StatusCode() int {
+	return http.StatusGone
+}
+
+type Unauthorized struct {
+	error
+}
+
+func NewUnauthorized(err string) *Unauthorized {
+	return &Unauthorized{errors.New(err)}
+}
+
+func (e *Unauthorized) StatusCode() int {
+	return http.StatusUnauthorized
+}
@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gkatanacio/marvel-characters-api/internal/errs"
+)
+
+// characterFields are the fields of a character that can be selected with the `fields` query param.
+var characterFields = []string{"id", "name", "description", "modified", "resourceURI", "thumbnail", "urls"}
+
+// searchResultFields are the fields of a search result that can be selected with the `fields` query param.
+var searchResultFields = append(append([]string{}, characterFields...), "score")
+
+// expandableFields are the related resource summaries of a character, which are
+// only included when asked for with the `expand` query param.
+var expandableFields = []string{"comics", "series", "stories", "events"}
+
+// fieldSelection holds the fields to include in a response.
+type fieldSelection struct {
+	fields map[string]bool // nil if all (non-expandable) fields are selected
+	expand map[string]bool
+}
+
+// parseFieldSelection reads the `fields` and `expand` query params of the request, which are
+// comma-separated lists of the `valid` fields and of the expandable fields respectively.
+func parseFieldSelection(r *http.Request, valid []string) (*fieldSelection, error) {
+	fields, err := parseFieldList(r.URL.Query().Get("fields"), "fields", valid)
+	if err != nil {
+		return nil, err
+	}
+
+	expand, err := parseFieldList(r.URL.Query().Get("expand"), "expand", expandableFields)
+	if err != nil {
+		return nil, err
+	}
+	if expand == nil {
+		expand = map[string]bool{}
+	}
+
+	return &fieldSelection{fields: fields, expand: expand}, nil
+}
+
+// parseFieldList splits the comma-separated list of fields `v`, returning nil if it is empty.
+func parseFieldList(v, param string, valid []string) (map[string]bool, error) {
+	if strings.TrimSpace(v) == "" {
+		return nil, nil
+	}
+
+	validSet := make(map[string]bool, len(valid))
+	for _, f := range valid {
+		validSet[f] = true
+	}
+
+	list := make(map[string]bool)
+	var unknown []string
+	for _, f := range strings.Split(v, ",") {
+		f = strings.TrimSpace(f)
+		if f == "" {
+			continue
+		}
+		if !validSet[f] {
+			unknown = append(unknown, f)
+			continue
+		}
+		list[f] = true
+	}
+
+	if len(unknown) > 0 {
+		return nil, errs.NewBadRequest(fmt.Sprintf("invalid %s: %s, valid fields: %s", param, strings.Join(unknown, ", "), strings.Join(valid, ", ")))
+	}
+
+	return list, nil
+}
+
+// apply trims the JSON representation of `v` down to the selected fields.
+func (fs *fieldSelection) apply(v interface{}) (map[string]json.RawMessage, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	obj := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return nil, err
+	}
+
+	fs.trim(obj)
+	return obj, nil
+}
+
+// applyAll trims the JSON representation of each element of the slice `v` down to the selected fields.
+func (fs *fieldSelection) applyAll(v interface{}) ([]map[string]json.RawMessage, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	objs := []map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &objs); err != nil {
+		return nil, err
+	}
+
+	for _, obj := range objs {
+		fs.trim(obj)
+	}
+	return objs, nil
+}
+
+func (fs *fieldSelection) trim(obj map[string]json.RawMessage) {
+	for k := range obj {
+		if !fs.includes(k) {
+			delete(obj, k)
+		}
+	}
+}
+
+func (fs *fieldSelection) includes(field string) bool {
+	for _, f := range expandableFields {
+		if f == field {
+			return fs.expand[field]
+		}
+	}
+	return fs.fields == nil || fs.fields[field]
+}
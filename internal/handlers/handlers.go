@@ -1,46 +1,291 @@
 package handlers
 
 import (
-	"log"
+	"encoding/json"
+	"fmt"
 	"net/http"
+	"sort"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gkatanacio/marvel-characters-api/internal/errs"
+	"github.com/gkatanacio/marvel-characters-api/internal/logging"
 	"github.com/gkatanacio/marvel-characters-api/internal/marvel"
 	"github.com/gorilla/mux"
 )
 
 type GetAllCharactersHandler struct {
 	marvelService marvel.Servicer
+	logger        *logging.Logger
 }
 
-func NewGetAllCharactersHandler(marvelService marvel.Servicer) *GetAllCharactersHandler {
-	return &GetAllCharactersHandler{marvelService}
+func NewGetAllCharactersHandler(marvelService marvel.Servicer, logger *logging.Logger) *GetAllCharactersHandler {
+	return &GetAllCharactersHandler{marvelService, logger}
 }
 
 // GetAllCharacters godoc
 // @summary Get all Character IDs
+// @description Character IDs are returned as a bare array, unless limit or offset is given,
+// @description in which case a page of them is returned in an envelope with links to the adjacent pages.
+// @description When searching by name or nameStartsWith, a page of the matching characters
+// @description (sorted by name) is returned in the envelope instead.
+// @description When looking up ids, the characters found are returned (in the order of the ids),
+// @description along with an error for each ID that could not be looked up.
 // @tags Characters
 // @produce json
+// @param ids query string false "Comma-separated IDs of the characters to look up (up to 100)"
+// @param name query string false "Name of the characters to search for (case-insensitive)"
+// @param nameStartsWith query string false "Beginning of the name of the characters to search for (case-insensitive)"
+// @param limit query int false "Maximum number of IDs to return (1 to 100, default 20, not allowed with ids)"
+// @param offset query int false "Number of IDs to skip (not allowed with ids)"
+// @param orderBy query string false "Order of the IDs (id, name or modified, prefixed with - for descending order, id by default)"
+// @param fields query string false "Comma-separated fields of the characters looked up or searched by name to include (id, name, description, modified, resourceURI, thumbnail, urls, all by default)"
+// @param expand query string false "Comma-separated related resource summaries of the characters looked up or searched by name to include (comics, series, stories, events)"
 // @success 200 {array} integer
+// @header 200 {string} X-Last-Synced "Time of the last successful sync with Marvel's API (RFC 3339)"
+// @header 200 {integer} Age "Seconds since the last successful sync with Marvel's API"
+// @header 200 {string} X-Cache-Status "stale if the last sync with Marvel's API failed, fresh otherwise"
 // @router /characters [get]
 func (h *GetAllCharactersHandler) Handle(w http.ResponseWriter, r *http.Request) {
-	charIds, err := h.marvelService.GetAllCharacterIds()
+	p, err := parsePage(r)
 	if err != nil {
-		log.Println(err)
 		errorResponse(w, err)
 		return
 	}
 
-	jsonResponse(w, charIds, http.StatusOK)
+	q := r.URL.Query()
+	if _, hasIds := q["ids"]; hasIds {
+		if p != nil {
+			errorResponse(w, errs.NewBadRequest("limit and offset cannot be combined with ids"))
+			return
+		}
+		h.handleBatch(w, r)
+		return
+	}
+
+	_, hasName := q["name"]
+	_, hasNameStartsWith := q["nameStartsWith"]
+	if hasName || hasNameStartsWith {
+		h.handleSearch(w, r, p)
+		return
+	}
+
+	orderBy, desc, err := parseOrderBy(r, "id", "id", "name", "modified")
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	var charIds []int
+	if orderBy == "id" {
+		charIds, err = h.marvelService.GetAllCharacterIds(r.Context())
+	} else {
+		charIds, err = h.marvelService.GetAllCharacterIdsOrdered(r.Context(), r.URL.Query().Get("orderBy"))
+	}
+	if err != nil {
+		logError(h.logger, r, err)
+		errorResponse(w, err)
+		return
+	}
+
+	if orderBy == "id" && desc {
+		sort.Sort(sort.Reverse(sort.IntSlice(charIds)))
+	}
+
+	syncStatus := h.marvelService.SyncStatus()
+	if !syncStatus.LastSuccess.IsZero() {
+		w.Header().Set("X-Last-Synced", syncStatus.LastSuccess.UTC().Format(time.RFC3339))
+		w.Header().Set("Age", strconv.Itoa(int(time.Since(syncStatus.LastSuccess).Seconds())))
+	}
+	if syncStatus.Stale() {
+		w.Header().Set("X-Cache-Status", "stale")
+		w.Header().Set("Warning", `110 - "Response is Stale"`)
+	} else {
+		w.Header().Set("X-Cache-Status", "fresh")
+	}
+
+	if p == nil {
+		jsonResponse(w, charIds, http.StatusOK)
+		return
+	}
+
+	start, end := p.bounds(len(charIds))
+	jsonResponse(w, newPageResponseBody(r, p, len(charIds), end-start, charIds[start:end]), http.StatusOK)
+}
+
+func (h *GetAllCharactersHandler) handleSearch(w http.ResponseWriter, r *http.Request, p *page) {
+	search := marvel.CharacterSearch{
+		Name:           strings.TrimSpace(r.URL.Query().Get("name")),
+		NameStartsWith: strings.TrimSpace(r.URL.Query().Get("nameStartsWith")),
+	}
+
+	if (search.Name == "") == (search.NameStartsWith == "") {
+		errorResponse(w, errs.NewBadRequest("exactly one of name and nameStartsWith must be given (and not be empty)"))
+		return
+	}
+
+	fs, err := parseFieldSelection(r, characterFields)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	if p == nil {
+		p = &page{limit: defaultPageLimit}
+	}
+	search.Limit = p.limit
+	search.Offset = p.offset
+
+	charPage, err := h.marvelService.SearchCharacters(r.Context(), search)
+	if err != nil {
+		logError(h.logger, r, err)
+		errorResponse(w, err)
+		return
+	}
+
+	chars, err := fs.applyAll(charPage.Characters)
+	if err != nil {
+		logError(h.logger, r, err)
+		errorResponse(w, err)
+		return
+	}
+
+	jsonResponse(w, newPageResponseBody(r, p, charPage.Total, len(chars), chars), http.StatusOK)
+}
+
+// maxBatchIds is the maximum number of characters looked up at once.
+const maxBatchIds = 100
+
+type batchResponseBody struct {
+	Results []map[string]json.RawMessage `json:"results"`
+	Errors  []*marvel.BatchError         `json:"errors"`
+}
+
+func (h *GetAllCharactersHandler) handleBatch(w http.ResponseWriter, r *http.Request) {
+	ids, err := parseIds(r.URL.Query().Get("ids"))
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	fs, err := parseFieldSelection(r, characterFields)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	batch, err := h.marvelService.GetCharacters(r.Context(), ids)
+	if err != nil {
+		logError(h.logger, r, err)
+		errorResponse(w, err)
+		return
+	}
+
+	chars, err := fs.applyAll(batch.Characters)
+	if err != nil {
+		logError(h.logger, r, err)
+		errorResponse(w, err)
+		return
+	}
+
+	jsonResponse(w, &batchResponseBody{Results: chars, Errors: batch.Errors}, http.StatusOK)
+}
+
+// parseIds parses a comma-separated list of character IDs.
+func parseIds(v string) ([]int, error) {
+	var ids []int
+	for _, s := range strings.Split(v, ",") {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			continue
+		}
+		id, err := strconv.Atoi(s)
+		if err != nil {
+			return nil, errs.NewBadRequest("invalid id: " + s)
+		}
+		ids = append(ids, id)
+	}
+
+	if len(ids) == 0 || len(ids) > maxBatchIds {
+		return nil, errs.NewBadRequest(fmt.Sprintf("ids must list between 1 and %d IDs", maxBatchIds))
+	}
+	return ids, nil
+}
+
+type GetChangesHandler struct {
+	marvelService marvel.Servicer
+	logger        *logging.Logger
+}
+
+func NewGetChangesHandler(marvelService marvel.Servicer, logger *logging.Logger) *GetChangesHandler {
+	return &GetChangesHandler{marvelService, logger}
+}
+
+// GetChanges godoc
+// @summary Get the Characters added, modified and removed since a given time
+// @description Changes are found by the syncs with Marvel's API and listed in the order they were found.
+// @description Each response comes with a cursor to resume from, to get the changes that follow.
+// @description Changes made too long ago to still be available result in a 410 error,
+// @description after which the full list of characters should be fetched again.
+// @tags Characters
+// @produce json
+// @param since query string false "Time since which to list changes (RFC 3339), required unless cursor is given"
+// @param cursor query string false "Cursor returned by a previous request, to list the changes that followed"
+// @param limit query int false "Maximum number of changes to return (1 to 100, default 100)"
+// @success 200 {object} marvel.ChangePage
+// @router /characters/changes [get]
+func (h *GetChangesHandler) Handle(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	query := marvel.ChangeQuery{
+		Cursor: q.Get("cursor"),
+		Limit:  maxPageLimit,
+	}
+
+	since := q.Get("since")
+	if (since == "") == (query.Cursor == "") {
+		errorResponse(w, errs.NewBadRequest("exactly one of since and cursor must be given"))
+		return
+	}
+	if since != "" {
+		t, err := time.Parse(time.RFC3339, since)
+		if err != nil {
+			errorResponse(w, errs.NewBadRequest("since must be a time in RFC 3339 format"))
+			return
+		}
+		query.Since = t
+	}
+
+	p, err := parsePage(r)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+	if p != nil {
+		if p.offset != 0 {
+			errorResponse(w, errs.NewBadRequest("offset is not supported, use cursor instead"))
+			return
+		}
+		query.Limit = p.limit
+	}
+
+	changes, err := h.marvelService.GetChanges(r.Context(), query)
+	if err != nil {
+		logError(h.logger, r, err)
+		errorResponse(w, err)
+		return
+	}
+
+	jsonResponse(w, changes, http.StatusOK)
 }
 
 type GetCharacterInfoHandler struct {
 	marvelService marvel.Servicer
+	logger        *logging.Logger
 }
 
-func NewGetCharacterInfoHandler(marvelService marvel.Servicer) *GetCharacterInfoHandler {
-	return &GetCharacterInfoHandler{marvelService}
+func NewGetCharacterInfoHandler(marvelService marvel.Servicer, logger *logging.Logger) *GetCharacterInfoHandler {
+	return &GetCharacterInfoHandler{marvelService, logger}
 }
 
 // GetCharacterInfo godoc
@@ -48,6 +293,9 @@ func NewGetCharacterInfoHandler(marvelService marvel.Servicer) *GetCharacterInfo
 // @tags Characters
 // @produce json
 // @param id path int true "Character ID"
+// @param fields query string false "Comma-separated fields to include (id, name, description, modified, resourceURI, thumbnail, urls, all by default)"
+// @param expand query string false "Comma-separated related resource summaries to include (comics, series, stories, events)"
+// @param imageVariant query string false "Variant of the thumbnail URL (e.g. portrait_xlarge or standard_fantastic, full size by default)"
 // @success 200 {object} marvel.Character
 // @router /characters/{id} [get]
 func (h *GetCharacterInfoHandler) Handle(w http.ResponseWriter, r *http.Request) {
@@ -56,17 +304,230 @@ func (h *GetCharacterInfoHandler) Handle(w http.ResponseWriter, r *http.Request)
 
 	charId, err := strconv.Atoi(id)
 	if err != nil {
-		log.Println(err)
 		errorResponse(w, errs.NewBadRequest("invalid id"))
 		return
 	}
 
-	char, err := h.marvelService.GetCharacter(charId)
+	imageVariant, ok := marvel.ParseImageVariant(r.URL.Query().Get("imageVariant"))
+	if !ok {
+		errorResponse(w, errs.NewBadRequest("imageVariant must be one of: "+joinImageVariants(marvel.ImageVariants)))
+		return
+	}
+
+	fs, err := parseFieldSelection(r, characterFields)
 	if err != nil {
-		log.Println(err)
 		errorResponse(w, err)
 		return
 	}
 
-	jsonResponse(w, char, http.StatusOK)
+	char, err := h.marvelService.GetCharacter(r.Context(), charId)
+	if err != nil {
+		logError(h.logger, r, err)
+		errorResponse(w, err)
+		return
+	}
+
+	if char.Thumbnail != nil && imageVariant != marvel.ImageFullSize {
+		char.Thumbnail = char.Thumbnail.WithVariant(imageVariant)
+	}
+
+	charFields, err := fs.apply(char)
+	if err != nil {
+		logError(h.logger, r, err)
+		errorResponse(w, err)
+		return
+	}
+
+	jsonResponse(w, charFields, http.StatusOK)
+}
+
+func joinImageVariants(variants []marvel.ImageVariant) string {
+	names := make([]string, 0, len(variants))
+	for _, v := range variants {
+		names = append(names, string(v))
+	}
+	return strings.Join(names, ", ")
+}
+
+type GetConnectionsHandler struct {
+	marvelService marvel.Servicer
+	logger        *logging.Logger
+}
+
+func NewGetConnectionsHandler(marvelService marvel.Servicer, logger *logging.Logger) *GetConnectionsHandler {
+	return &GetConnectionsHandler{marvelService, logger}
+}
+
+// GetConnections godoc
+// @summary Get the Characters appearing in comics together with a Character
+// @description A page of the characters sharing comics with the character is returned in an envelope,
+// @description from the one sharing the most comics to the one sharing the least.
+// @tags Characters
+// @produce json
+// @param id path int true "Character ID"
+// @param limit query int false "Maximum number of characters to return (1 to 100, default 20)"
+// @param offset query int false "Number of characters to skip"
+// @success 200 {array} marvel.Connection
+// @router /characters/{id}/connections [get]
+func (h *GetConnectionsHandler) Handle(w http.ResponseWriter, r *http.Request) {
+	charId, err := parseIntVar(r, "id")
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	p, query, err := parseResourceQuery(r)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	conns, total, err := h.marvelService.GetConnections(r.Context(), charId, query)
+	if err != nil {
+		logError(h.logger, r, err)
+		errorResponse(w, err)
+		return
+	}
+
+	jsonResponse(w, newPageResponseBody(r, p, total, len(conns), conns), http.StatusOK)
+}
+
+type GetPathHandler struct {
+	marvelService marvel.Servicer
+	logger        *logging.Logger
+}
+
+func NewGetPathHandler(marvelService marvel.Servicer, logger *logging.Logger) *GetPathHandler {
+	return &GetPathHandler{marvelService, logger}
+}
+
+// GetPath godoc
+// @summary Get the shortest chain of Characters linking two Characters through shared comics
+// @description The chain includes both characters. Each character after the first comes with
+// @description the number of comics shared with the previous one.
+// @tags Characters
+// @produce json
+// @param id path int true "Character ID"
+// @param toId path int true "ID of the Character to link to"
+// @success 200 {array} marvel.Connection
+// @router /characters/{id}/path/{toId} [get]
+func (h *GetPathHandler) Handle(w http.ResponseWriter, r *http.Request) {
+	from, err := parseIntVar(r, "id")
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	to, err := parseIntVar(r, "toId")
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	path, err := h.marvelService.GetPath(r.Context(), from, to)
+	if err != nil {
+		logError(h.logger, r, err)
+		errorResponse(w, err)
+		return
+	}
+
+	jsonResponse(w, path, http.StatusOK)
+}
+
+type SearchHandler struct {
+	marvelService marvel.Servicer
+	logger        *logging.Logger
+}
+
+func NewSearchHandler(marvelService marvel.Servicer, logger *logging.Logger) *SearchHandler {
+	return &SearchHandler{marvelService, logger}
+}
+
+// Search godoc
+// @summary Search characters
+// @description Characters are matched by name (tolerating typos and missing spaces or hyphens)
+// @description and by the words of their descriptions. A page of them is returned in an envelope,
+// @description ranked by relevance, each with its score.
+// @tags Characters
+// @produce json
+// @param q query string true "Search query"
+// @param limit query int false "Maximum number of characters to return (1 to 100, default 20)"
+// @param offset query int false "Number of characters to skip"
+// @param fields query string false "Comma-separated fields to include (id, name, description, modified, resourceURI, thumbnail, urls, score, all by default)"
+// @param expand query string false "Comma-separated related resource summaries to include (comics, series, stories, events)"
+// @success 200 {array} marvel.SearchResult
+// @router /search [get]
+func (h *SearchHandler) Handle(w http.ResponseWriter, r *http.Request) {
+	query := strings.TrimSpace(r.URL.Query().Get("q"))
+	if query == "" {
+		errorResponse(w, errs.NewBadRequest("q must be given (and not be empty)"))
+		return
+	}
+
+	p, err := parsePage(r)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+	if p == nil {
+		p = &page{limit: defaultPageLimit}
+	}
+
+	fs, err := parseFieldSelection(r, searchResultFields)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	searchPage, err := h.marvelService.Search(r.Context(), query, p.limit, p.offset)
+	if err != nil {
+		logError(h.logger, r, err)
+		errorResponse(w, err)
+		return
+	}
+
+	results, err := fs.applyAll(searchPage.Results)
+	if err != nil {
+		logError(h.logger, r, err)
+		errorResponse(w, err)
+		return
+	}
+
+	jsonResponse(w, newPageResponseBody(r, p, searchPage.Total, len(results), results), http.StatusOK)
+}
+
+type GetBudgetHandler struct {
+	budget marvel.BudgetReporter
+}
+
+func NewGetBudgetHandler(budget marvel.BudgetReporter) *GetBudgetHandler {
+	return &GetBudgetHandler{budget}
+}
+
+// GetBudget godoc
+// @summary Get the remaining Marvel API call budget
+// @tags Status
+// @produce json
+// @success 200 {object} marvel.BudgetStatus
+// @router /budget [get]
+func (h *GetBudgetHandler) Handle(w http.ResponseWriter, r *http.Request) {
+	jsonResponse(w, h.budget.Status(), http.StatusOK)
+}
+
+type GetSyncStatusHandler struct {
+	marvelService marvel.Servicer
+}
+
+func NewGetSyncStatusHandler(marvelService marvel.Servicer) *GetSyncStatusHandler {
+	return &GetSyncStatusHandler{marvelService}
+}
+
+// GetSyncStatus godoc
+// @summary Get the status of the cache syncs with Marvel's API
+// @tags Status
+// @produce json
+// @success 200 {object} marvel.SyncStatus
+// @router /sync [get]
+func (h *GetSyncStatusHandler) Handle(w http.ResponseWriter, r *http.Request) {
+	jsonResponse(w, h.marvelService.SyncStatus(), http.StatusOK)
 }
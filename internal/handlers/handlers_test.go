@@ -4,21 +4,28 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/gkatanacio/marvel-characters-api/internal/handlers"
+	"github.com/gkatanacio/marvel-characters-api/internal/logging"
 	"github.com/gkatanacio/marvel-characters-api/internal/marvel"
 	"github.com/gkatanacio/marvel-characters-api/mocks"
 	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func Test_GetAllCharactersHandler_Handle_HappyPath(t *testing.T) {
 	// given
 	marvelServiceMock := new(mocks.Servicer)
-	marvelServiceMock.On("GetAllCharacterIds").Return([]int{1009351, 1011490, 1011001, 1009595}, nil)
+	marvelServiceMock.On("GetAllCharacterIds", mock.Anything).Return([]int{1009351, 1011490, 1011001, 1009595}, nil)
+	marvelServiceMock.On("SyncStatus").Return(marvel.SyncStatus{
+		LastSuccess: time.Date(2021, 3, 1, 10, 0, 0, 0, time.UTC),
+	})
 
-	handler := handlers.NewGetAllCharactersHandler(marvelServiceMock)
+	handler := handlers.NewGetAllCharactersHandler(marvelServiceMock, logging.Discard())
 
 	rr := httptest.NewRecorder()
 
@@ -29,21 +36,272 @@ func Test_GetAllCharactersHandler_Handle_HappyPath(t *testing.T) {
 
 	// then
 	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "2021-03-01T10:00:00Z", rr.Header().Get("X-Last-Synced"))
+	assert.Equal(t, "fresh", rr.Header().Get("X-Cache-Status"))
 	marvelServiceMock.AssertExpectations(t)
 }
 
+func Test_GetAllCharactersHandler_Handle_Stale(t *testing.T) {
+	// given
+	marvelServiceMock := new(mocks.Servicer)
+	marvelServiceMock.On("GetAllCharacterIds", mock.Anything).Return([]int{1009351, 1011490}, nil)
+	marvelServiceMock.On("SyncStatus").Return(marvel.SyncStatus{
+		LastSuccess: time.Now().Add(-10 * time.Minute),
+		LastAttempt: time.Now(),
+		LastError:   "error response from marvel api",
+	})
+
+	handler := handlers.NewGetAllCharactersHandler(marvelServiceMock, logging.Discard())
+
+	rr := httptest.NewRecorder()
+
+	req, _ := http.NewRequest(http.MethodGet, "/characters", nil)
+
+	// when
+	handler.Handle(rr, req)
+
+	// then
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "stale", rr.Header().Get("X-Cache-Status"))
+	assert.Equal(t, "600", rr.Header().Get("Age"))
+	marvelServiceMock.AssertExpectations(t)
+}
+
+func Test_GetAllCharactersHandler_Handle_Paginated(t *testing.T) {
+	// given
+	marvelServiceMock := new(mocks.Servicer)
+	marvelServiceMock.On("GetAllCharacterIds", mock.Anything).Return([]int{1009351, 1009595, 1011001, 1011490}, nil)
+	marvelServiceMock.On("SyncStatus").Return(marvel.SyncStatus{})
+
+	handler := handlers.NewGetAllCharactersHandler(marvelServiceMock, logging.Discard())
+
+	rr := httptest.NewRecorder()
+
+	req, _ := http.NewRequest(http.MethodGet, "/characters?limit=2&offset=1&orderBy=-id", nil)
+
+	// when
+	handler.Handle(rr, req)
+
+	// then
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{
+		"total": 4,
+		"count": 2,
+		"limit": 2,
+		"offset": 1,
+		"links": {
+			"next": "/characters?limit=2&offset=3&orderBy=-id",
+			"prev": "/characters?limit=2&offset=0&orderBy=-id"
+		},
+		"results": [1011001, 1009595]
+	}`, rr.Body.String())
+	marvelServiceMock.AssertExpectations(t)
+}
+
+func Test_GetAllCharactersHandler_Handle_OrderedByName(t *testing.T) {
+	// given
+	marvelServiceMock := new(mocks.Servicer)
+	marvelServiceMock.On("GetAllCharacterIdsOrdered", mock.Anything, "-name").Return([]int{1009610, 1009351, 1009187}, nil)
+	marvelServiceMock.On("SyncStatus").Return(marvel.SyncStatus{})
+
+	handler := handlers.NewGetAllCharactersHandler(marvelServiceMock, logging.Discard())
+
+	rr := httptest.NewRecorder()
+
+	req, _ := http.NewRequest(http.MethodGet, "/characters?orderBy=-name", nil)
+
+	// when
+	handler.Handle(rr, req)
+
+	// then
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `[1009610, 1009351, 1009187]`, rr.Body.String())
+	marvelServiceMock.AssertExpectations(t)
+}
+
+func Test_GetAllCharactersHandler_Handle_InvalidPagination(t *testing.T) {
+	// given
+	handler := handlers.NewGetAllCharactersHandler(nil, logging.Discard())
+
+	for _, query := range []string{"limit=0", "limit=101", "limit=abc", "offset=-1", "orderBy=description"} {
+		rr := httptest.NewRecorder()
+
+		req, _ := http.NewRequest(http.MethodGet, "/characters?"+query, nil)
+
+		// when
+		handler.Handle(rr, req)
+
+		// then
+		assert.Equal(t, http.StatusBadRequest, rr.Code, query)
+	}
+}
+
+func Test_GetAllCharactersHandler_Handle_NameSearch(t *testing.T) {
+	// given
+	marvelServiceMock := new(mocks.Servicer)
+	marvelServiceMock.On("SearchCharacters", mock.Anything, marvel.CharacterSearch{
+		NameStartsWith: "spider",
+		Limit:          20,
+	}).Return(&marvel.CharacterPage{
+		Total: 1,
+		Characters: []*marvel.Character{
+			{Id: 1009610, Name: "Spider-Man"},
+		},
+	}, nil)
+
+	handler := handlers.NewGetAllCharactersHandler(marvelServiceMock, logging.Discard())
+
+	rr := httptest.NewRecorder()
+
+	req, _ := http.NewRequest(http.MethodGet, "/characters?nameStartsWith=spider", nil)
+
+	// when
+	handler.Handle(rr, req)
+
+	// then
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{
+		"total": 1,
+		"count": 1,
+		"limit": 20,
+		"offset": 0,
+		"links": {},
+		"results": [{"id": 1009610, "name": "Spider-Man", "description": ""}]
+	}`, rr.Body.String())
+	marvelServiceMock.AssertExpectations(t)
+}
+
+func Test_GetAllCharactersHandler_Handle_InvalidNameSearch(t *testing.T) {
+	// given
+	handler := handlers.NewGetAllCharactersHandler(nil, logging.Discard())
+
+	for _, query := range []string{"name=", "name=Hulk&nameStartsWith=Hu", "nameStartsWith=%20"} {
+		rr := httptest.NewRecorder()
+
+		req, _ := http.NewRequest(http.MethodGet, "/characters?"+query, nil)
+
+		// when
+		handler.Handle(rr, req)
+
+		// then
+		assert.Equal(t, http.StatusBadRequest, rr.Code, query)
+	}
+}
+
+func Test_GetAllCharactersHandler_Handle_Batch(t *testing.T) {
+	// given
+	marvelServiceMock := new(mocks.Servicer)
+	marvelServiceMock.On("GetCharacters", mock.Anything, []int{1009351, 1, 1009610}).Return(&marvel.CharacterBatch{
+		Characters: []*marvel.Character{
+			{Id: 1009351, Name: "Hulk"},
+			{Id: 1009610, Name: "Spider-Man"},
+		},
+		Errors: []*marvel.BatchError{
+			{Id: 1, Status: http.StatusNotFound, Error: "no results"},
+		},
+	}, nil)
+
+	handler := handlers.NewGetAllCharactersHandler(marvelServiceMock, logging.Discard())
+
+	rr := httptest.NewRecorder()
+
+	req, _ := http.NewRequest(http.MethodGet, "/characters?ids=1009351,1,1009610&fields=id,name", nil)
+
+	// when
+	handler.Handle(rr, req)
+
+	// then
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{
+		"results": [{"id": 1009351, "name": "Hulk"}, {"id": 1009610, "name": "Spider-Man"}],
+		"errors": [{"id": 1, "status": 404, "error": "no results"}]
+	}`, rr.Body.String())
+	marvelServiceMock.AssertExpectations(t)
+}
+
+func Test_GetAllCharactersHandler_Handle_InvalidBatch(t *testing.T) {
+	// given
+	handler := handlers.NewGetAllCharactersHandler(nil, logging.Discard())
+
+	tooMany := make([]string, 101)
+	for i := range tooMany {
+		tooMany[i] = strconv.Itoa(i + 1)
+	}
+
+	for _, ids := range []string{"", "1,abc", strings.Join(tooMany, ","), "1,2&limit=1", "1,2&offset=1"} {
+		rr := httptest.NewRecorder()
+
+		req, _ := http.NewRequest(http.MethodGet, "/characters?ids="+ids, nil)
+
+		// when
+		handler.Handle(rr, req)
+
+		// then
+		assert.Equal(t, http.StatusBadRequest, rr.Code, ids)
+	}
+}
+
+func Test_GetChangesHandler_Handle_HappyPath(t *testing.T) {
+	// given
+	since, _ := time.Parse(time.RFC3339, "2021-03-01T10:00:00Z")
+	changeTime := since.Add(time.Minute)
+
+	marvelServiceMock := new(mocks.Servicer)
+	marvelServiceMock.On("GetChanges", mock.Anything, marvel.ChangeQuery{Since: since, Limit: 100}).Return(&marvel.ChangePage{
+		Changes: []*marvel.Change{
+			{Id: 1009351, Type: marvel.ChangeRemoved, Time: changeTime},
+		},
+		Cursor: "1614592800000000000.7",
+	}, nil)
+
+	handler := handlers.NewGetChangesHandler(marvelServiceMock, logging.Discard())
+
+	rr := httptest.NewRecorder()
+
+	req, _ := http.NewRequest(http.MethodGet, "/characters/changes?since=2021-03-01T10:00:00Z", nil)
+
+	// when
+	handler.Handle(rr, req)
+
+	// then
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{
+		"changes": [{"id": 1009351, "type": "removed", "time": "2021-03-01T10:01:00Z"}],
+		"cursor": "1614592800000000000.7",
+		"hasMore": false
+	}`, rr.Body.String())
+	marvelServiceMock.AssertExpectations(t)
+}
+
+func Test_GetChangesHandler_Handle_InvalidQuery(t *testing.T) {
+	// given
+	handler := handlers.NewGetChangesHandler(nil, logging.Discard())
+
+	for _, query := range []string{"", "since=yesterday", "since=2021-03-01T10:00:00Z&cursor=1.1", "cursor=1.1&offset=20", "cursor=1.1&limit=0"} {
+		rr := httptest.NewRecorder()
+
+		req, _ := http.NewRequest(http.MethodGet, "/characters/changes?"+query, nil)
+
+		// when
+		handler.Handle(rr, req)
+
+		// then
+		assert.Equal(t, http.StatusBadRequest, rr.Code, query)
+	}
+}
+
 func Test_GetCharacterInfoHandler_Handle_HappyPath(t *testing.T) {
 	// given
 	charId := 1009351
 
 	marvelServiceMock := new(mocks.Servicer)
-	marvelServiceMock.On("GetCharacter", charId).Return(&marvel.Character{
+	marvelServiceMock.On("GetCharacter", mock.Anything, charId).Return(&marvel.Character{
 		Id:          charId,
 		Name:        "Hulk",
 		Description: "An all too often misunderstood hero, the angrier the Hulk gets, the stronger the Hulk gets.",
 	}, nil)
 
-	handler := handlers.NewGetCharacterInfoHandler(marvelServiceMock)
+	handler := handlers.NewGetCharacterInfoHandler(marvelServiceMock, logging.Discard())
 
 	rr := httptest.NewRecorder()
 
@@ -59,11 +317,171 @@ func Test_GetCharacterInfoHandler_Handle_HappyPath(t *testing.T) {
 	marvelServiceMock.AssertExpectations(t)
 }
 
+func Test_GetCharacterInfoHandler_Handle_ImageVariant(t *testing.T) {
+	// given
+	charId := 1009351
+
+	marvelServiceMock := new(mocks.Servicer)
+	marvelServiceMock.On("GetCharacter", mock.Anything, charId).Return(&marvel.Character{
+		Id:   charId,
+		Name: "Hulk",
+		Thumbnail: &marvel.Thumbnail{
+			Path:      "http://i.annihil.us/u/prod/marvel/i/mg/5/a0/538615ca33ab0",
+			Extension: "jpg",
+			Url:       "http://i.annihil.us/u/prod/marvel/i/mg/5/a0/538615ca33ab0.jpg",
+		},
+	}, nil)
+
+	handler := handlers.NewGetCharacterInfoHandler(marvelServiceMock, logging.Discard())
+
+	rr := httptest.NewRecorder()
+
+	strCharId := strconv.Itoa(charId)
+	req, _ := http.NewRequest(http.MethodGet, "/characters/"+strCharId+"?imageVariant=portrait_xlarge", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": strCharId})
+
+	// when
+	handler.Handle(rr, req)
+
+	// then
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{
+		"id": 1009351,
+		"name": "Hulk",
+		"description": "",
+		"thumbnail": {
+			"path": "http://i.annihil.us/u/prod/marvel/i/mg/5/a0/538615ca33ab0",
+			"extension": "jpg",
+			"url": "http://i.annihil.us/u/prod/marvel/i/mg/5/a0/538615ca33ab0/portrait_xlarge.jpg"
+		}
+	}`, rr.Body.String())
+	marvelServiceMock.AssertExpectations(t)
+}
+
+func Test_GetCharacterInfoHandler_Handle_InvalidImageVariant(t *testing.T) {
+	// given
+	handler := handlers.NewGetCharacterInfoHandler(nil, logging.Discard())
+
+	rr := httptest.NewRecorder()
+
+	req, _ := http.NewRequest(http.MethodGet, "/characters/1009351?imageVariant=huge", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "1009351"})
+
+	// when
+	handler.Handle(rr, req)
+
+	// then
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, rr.Body.String(), "portrait_xlarge")
+}
+
+func Test_GetCharacterInfoHandler_Handle_Fields(t *testing.T) {
+	// given
+	charId := 1009351
+
+	comics := &marvel.ResourceList{
+		Available:     1,
+		Returned:      1,
+		CollectionURI: "http://gateway.marvel.com/v1/public/characters/1009351/comics",
+		Items: []*marvel.ResourceSummary{
+			{ResourceURI: "http://gateway.marvel.com/v1/public/comics/41112", Name: "5 Ronin (Marvel Edition) (2011) #2"},
+		},
+	}
+
+	marvelServiceMock := new(mocks.Servicer)
+	marvelServiceMock.On("GetCharacter", mock.Anything, charId).Return(&marvel.Character{
+		Id:          charId,
+		Name:        "Hulk",
+		Description: "An all too often misunderstood hero, the angrier the Hulk gets, the stronger the Hulk gets.",
+		Comics:      comics,
+		Series:      &marvel.ResourceList{Available: 0, CollectionURI: "http://gateway.marvel.com/v1/public/characters/1009351/series"},
+	}, nil)
+
+	handler := handlers.NewGetCharacterInfoHandler(marvelServiceMock, logging.Discard())
+
+	testCases := map[string]struct {
+		query    string
+		expected string
+	}{
+		"default": {
+			"",
+			`{"id": 1009351, "name": "Hulk", "description": "An all too often misunderstood hero, the angrier the Hulk gets, the stronger the Hulk gets."}`,
+		},
+		"fields": {
+			"?fields=id,name",
+			`{"id": 1009351, "name": "Hulk"}`,
+		},
+		"fields and expand": {
+			"?fields=name&expand=comics",
+			`{
+				"name": "Hulk",
+				"comics": {
+					"available": 1,
+					"returned": 1,
+					"collectionURI": "http://gateway.marvel.com/v1/public/characters/1009351/comics",
+					"items": [{"resourceURI": "http://gateway.marvel.com/v1/public/comics/41112", "name": "5 Ronin (Marvel Edition) (2011) #2"}]
+				}
+			}`,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+
+			req, _ := http.NewRequest(http.MethodGet, "/characters/1009351"+tc.query, nil)
+			req = mux.SetURLVars(req, map[string]string{"id": "1009351"})
+
+			// when
+			handler.Handle(rr, req)
+
+			// then
+			assert.Equal(t, http.StatusOK, rr.Code)
+			assert.JSONEq(t, tc.expected, rr.Body.String())
+		})
+	}
+}
+
+func Test_GetCharacterInfoHandler_Handle_UnknownFields(t *testing.T) {
+	// given
+	handler := handlers.NewGetCharacterInfoHandler(nil, logging.Discard())
+
+	testCases := map[string]struct {
+		query    string
+		expected string
+	}{
+		"fields": {
+			"fields=id,powers",
+			"invalid fields: powers, valid fields: id, name, description, modified, resourceURI, thumbnail, urls",
+		},
+		"expand": {
+			"expand=creators",
+			"invalid expand: creators, valid fields: comics, series, stories, events",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+
+			req, _ := http.NewRequest(http.MethodGet, "/characters/1009351?"+tc.query, nil)
+			req = mux.SetURLVars(req, map[string]string{"id": "1009351"})
+
+			// when
+			handler.Handle(rr, req)
+
+			// then
+			assert.Equal(t, http.StatusBadRequest, rr.Code)
+			assert.JSONEq(t, `{"error": "`+tc.expected+`"}`, rr.Body.String())
+		})
+	}
+}
+
 func Test_GetCharacterInfoHandler_Handle_InvalidId(t *testing.T) {
 	// given
 	charId := "123abc"
 
-	handler := handlers.NewGetCharacterInfoHandler(nil)
+	handler := handlers.NewGetCharacterInfoHandler(nil, logging.Discard())
 
 	rr := httptest.NewRecorder()
 
@@ -76,3 +494,176 @@ func Test_GetCharacterInfoHandler_Handle_InvalidId(t *testing.T) {
 	// then
 	assert.Equal(t, http.StatusBadRequest, rr.Code)
 }
+
+func Test_GetConnectionsHandler_Handle_HappyPath(t *testing.T) {
+	// given
+	marvelServiceMock := new(mocks.Servicer)
+	marvelServiceMock.On("GetConnections", mock.Anything, 1009351, marvel.ResourceQuery{Limit: 1}).Return([]*marvel.Connection{
+		{Id: 1009610, Name: "Spider-Man", SharedComics: 42},
+	}, 310, nil)
+
+	handler := handlers.NewGetConnectionsHandler(marvelServiceMock, logging.Discard())
+
+	rr := httptest.NewRecorder()
+
+	req, _ := http.NewRequest(http.MethodGet, "/characters/1009351/connections?limit=1", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "1009351"})
+
+	// when
+	handler.Handle(rr, req)
+
+	// then
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{
+		"total": 310,
+		"count": 1,
+		"limit": 1,
+		"offset": 0,
+		"links": {"next": "/characters/1009351/connections?limit=1&offset=1"},
+		"results": [{"id": 1009610, "name": "Spider-Man", "sharedComics": 42}]
+	}`, rr.Body.String())
+	marvelServiceMock.AssertExpectations(t)
+}
+
+func Test_GetPathHandler_Handle_HappyPath(t *testing.T) {
+	// given
+	marvelServiceMock := new(mocks.Servicer)
+	marvelServiceMock.On("GetPath", mock.Anything, 1009351, 1009368).Return([]*marvel.Connection{
+		{Id: 1009351, Name: "Hulk"},
+		{Id: 1009368, Name: "Iron Man", SharedComics: 120},
+	}, nil)
+
+	handler := handlers.NewGetPathHandler(marvelServiceMock, logging.Discard())
+
+	rr := httptest.NewRecorder()
+
+	req, _ := http.NewRequest(http.MethodGet, "/characters/1009351/path/1009368", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "1009351", "toId": "1009368"})
+
+	// when
+	handler.Handle(rr, req)
+
+	// then
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `[
+		{"id": 1009351, "name": "Hulk", "sharedComics": 0},
+		{"id": 1009368, "name": "Iron Man", "sharedComics": 120}
+	]`, rr.Body.String())
+	marvelServiceMock.AssertExpectations(t)
+}
+
+func Test_GetPathHandler_Handle_InvalidId(t *testing.T) {
+	// given
+	handler := handlers.NewGetPathHandler(nil, logging.Discard())
+
+	rr := httptest.NewRecorder()
+
+	req, _ := http.NewRequest(http.MethodGet, "/characters/1009351/path/abc", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "1009351", "toId": "abc"})
+
+	// when
+	handler.Handle(rr, req)
+
+	// then
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func Test_SearchHandler_Handle_HappyPath(t *testing.T) {
+	// given
+	marvelServiceMock := new(mocks.Servicer)
+	marvelServiceMock.On("Search", mock.Anything, "wolverene", 1, 0).Return(&marvel.SearchPage{
+		Total: 2,
+		Results: []*marvel.SearchResult{
+			{Character: &marvel.Character{Id: 1009718, Name: "Wolverine"}, Score: 6.4},
+		},
+	}, nil)
+
+	handler := handlers.NewSearchHandler(marvelServiceMock, logging.Discard())
+
+	rr := httptest.NewRecorder()
+
+	req, _ := http.NewRequest(http.MethodGet, "/search?q=wolverene&limit=1&fields=name,score", nil)
+
+	// when
+	handler.Handle(rr, req)
+
+	// then
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{
+		"total": 2,
+		"count": 1,
+		"limit": 1,
+		"offset": 0,
+		"links": {"next": "/search?fields=name%2Cscore&limit=1&offset=1&q=wolverene"},
+		"results": [{"name": "Wolverine", "score": 6.4}]
+	}`, rr.Body.String())
+	marvelServiceMock.AssertExpectations(t)
+}
+
+func Test_SearchHandler_Handle_MissingQuery(t *testing.T) {
+	// given
+	handler := handlers.NewSearchHandler(nil, logging.Discard())
+
+	for _, query := range []string{"", "q=", "q=%20"} {
+		rr := httptest.NewRecorder()
+
+		req, _ := http.NewRequest(http.MethodGet, "/search?"+query, nil)
+
+		// when
+		handler.Handle(rr, req)
+
+		// then
+		assert.Equal(t, http.StatusBadRequest, rr.Code, query)
+	}
+}
+
+func Test_GetBudgetHandler_Handle_HappyPath(t *testing.T) {
+	// given
+	budgetMock := new(mocks.BudgetReporter)
+	budgetMock.On("Status").Return(marvel.BudgetStatus{
+		Limit:     3000,
+		Used:      120,
+		Remaining: 2880,
+		Reserve:   100,
+	})
+
+	handler := handlers.NewGetBudgetHandler(budgetMock)
+
+	rr := httptest.NewRecorder()
+
+	req, _ := http.NewRequest(http.MethodGet, "/budget", nil)
+
+	// when
+	handler.Handle(rr, req)
+
+	// then
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"remaining":2880`)
+	budgetMock.AssertExpectations(t)
+}
+
+func Test_GetSyncStatusHandler_Handle_HappyPath(t *testing.T) {
+	// given
+	marvelServiceMock := new(mocks.Servicer)
+	marvelServiceMock.On("SyncStatus").Return(marvel.SyncStatus{
+		LastSuccess: time.Date(2021, 3, 1, 10, 0, 0, 0, time.UTC),
+		LastReconcile: &marvel.ReconcileResult{
+			Added:   2,
+			Removed: 1,
+		},
+	})
+
+	handler := handlers.NewGetSyncStatusHandler(marvelServiceMock)
+
+	rr := httptest.NewRecorder()
+
+	req, _ := http.NewRequest(http.MethodGet, "/sync", nil)
+
+	// when
+	handler.Handle(rr, req)
+
+	// then
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"removed":1`)
+	marvelServiceMock.AssertExpectations(t)
+}
@@ -0,0 +1,100 @@
+package handlers_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gkatanacio/marvel-characters-api/internal/errs"
+	"github.com/gkatanacio/marvel-characters-api/internal/handlers"
+	"github.com/gkatanacio/marvel-characters-api/internal/logging"
+	"github.com/gkatanacio/marvel-characters-api/mocks"
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_Instrument_RecordsRequestsByRoute(t *testing.T) {
+	// given
+	marvelServiceMock := new(mocks.Servicer)
+	marvelServiceMock.On("GetCharacter", mock.Anything, 1234).Return(nil, errs.NewNotFound("no results"))
+
+	r := mux.NewRouter()
+	r.Use(handlers.Instrument)
+	r.HandleFunc("/characters/{id}", handlers.NewGetCharacterInfoHandler(marvelServiceMock, logging.Discard()).Handle).Methods(http.MethodGet)
+	r.HandleFunc("/metrics", handlers.NewGetMetricsHandler(prometheus.DefaultGatherer).Handle).Methods(http.MethodGet)
+
+	requestsSeries := `http_requests_total{method="GET",route="/characters/{id}",status="404"}`
+	durationSeries := `http_request_duration_seconds_count{method="GET",route="/characters/{id}"}`
+
+	// the default registry is shared by every test run in the process, so only the increase is checked
+	before := scrapeMetrics(t, r)
+
+	req, _ := http.NewRequest(http.MethodGet, "/characters/1234", nil)
+
+	// when
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	// then
+	after := scrapeMetrics(t, r)
+	assert.Equal(t, 1.0, metricValue(after, requestsSeries)-metricValue(before, requestsSeries))
+	assert.Equal(t, 1.0, metricValue(after, durationSeries)-metricValue(before, durationSeries))
+	marvelServiceMock.AssertExpectations(t)
+}
+
+func Test_GetMetricsHandler_ServesTextFormat(t *testing.T) {
+	// given
+	registry := prometheus.NewRegistry()
+	promauto.With(registry).NewCounter(prometheus.CounterOpts{Name: "widgets_total", Help: "Widgets made."}).Inc()
+
+	rr := httptest.NewRecorder()
+
+	req, _ := http.NewRequest(http.MethodGet, "/metrics", nil)
+
+	// when
+	handlers.NewGetMetricsHandler(registry).Handle(rr, req)
+
+	// then
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "text/plain; version=0.0.4; charset=utf-8", rr.Header().Get("Content-Type"))
+	assert.Contains(t, rr.Body.String(), "widgets_total 1\n")
+}
+
+func Test_Instrument_KeepsResponsesFlushable(t *testing.T) {
+	// given
+	var flushable bool
+	handler := handlers.Instrument(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, flushable = w.(http.Flusher)
+	}))
+
+	req, _ := http.NewRequest(http.MethodGet, "/characters/stream", nil)
+
+	// when
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	// then
+	assert.True(t, flushable)
+}
+
+func scrapeMetrics(t *testing.T, h http.Handler) string {
+	rr := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/metrics", nil)
+	h.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	return rr.Body.String()
+}
+
+// metricValue returns the value of `series` in the scraped metrics, 0 if it has not been recorded yet.
+func metricValue(scraped string, series string) float64 {
+	for _, line := range strings.Split(scraped, "\n") {
+		if strings.HasPrefix(line, series+" ") {
+			v, _ := strconv.ParseFloat(strings.TrimPrefix(line, series+" "), 64)
+			return v
+		}
+	}
+	return 0
+}
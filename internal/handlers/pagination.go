@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gkatanacio/marvel-characters-api/internal/errs"
+)
+
+const (
+	defaultPageLimit = 20
+	maxPageLimit     = 100
+)
+
+// page holds the pagination query params of a request.
+type page struct {
+	limit  int
+	offset int
+}
+
+// parsePage reads the `limit` and `offset` query params of the request.
+// It returns nil if neither of them is given.
+func parsePage(r *http.Request) (*page, error) {
+	q := r.URL.Query()
+	if q.Get("limit") == "" && q.Get("offset") == "" {
+		return nil, nil
+	}
+
+	p := &page{
+		limit: defaultPageLimit,
+	}
+
+	if v := q.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 1 || limit > maxPageLimit {
+			return nil, errs.NewBadRequest(fmt.Sprintf("limit must be an integer between 1 and %d", maxPageLimit))
+		}
+		p.limit = limit
+	}
+
+	if v := q.Get("offset"); v != "" {
+		offset, err := strconv.Atoi(v)
+		if err != nil || offset < 0 {
+			return nil, errs.NewBadRequest("offset must be a non-negative integer")
+		}
+		p.offset = offset
+	}
+
+	return p, nil
+}
+
+// bounds returns the start and end indices of the page within `total` results.
+func (p *page) bounds(total int) (int, int) {
+	start := p.offset
+	if start > total {
+		start = total
+	}
+	end := start + p.limit
+	if end > total {
+		end = total
+	}
+	return start, end
+}
+
+type pageLinks struct {
+	Next string `json:"next,omitempty"`
+	Prev string `json:"prev,omitempty"`
+}
+
+type pageResponseBody struct {
+	Total   int         `json:"total"`
+	Count   int         `json:"count"`
+	Limit   int         `json:"limit"`
+	Offset  int         `json:"offset"`
+	Links   pageLinks   `json:"links"`
+	Results interface{} `json:"results"`
+}
+
+// newPageResponseBody wraps a page of `count` results (out of `total`) in an envelope
+// with links to the next and previous pages.
+func newPageResponseBody(r *http.Request, p *page, total, count int, results interface{}) *pageResponseBody {
+	body := &pageResponseBody{
+		Total:   total,
+		Count:   count,
+		Limit:   p.limit,
+		Offset:  p.offset,
+		Results: results,
+	}
+
+	if p.offset+p.limit < total {
+		body.Links.Next = pageLink(r, p.limit, p.offset+p.limit)
+	}
+	if p.offset > 0 {
+		prevOffset := p.offset - p.limit
+		if prevOffset < 0 {
+			prevOffset = 0
+		}
+		body.Links.Prev = pageLink(r, p.limit, prevOffset)
+	}
+
+	return body
+}
+
+func pageLink(r *http.Request, limit, offset int) string {
+	q := r.URL.Query()
+	q.Set("limit", strconv.Itoa(limit))
+	q.Set("offset", strconv.Itoa(offset))
+	return r.URL.Path + "?" + q.Encode()
+}
+
+// parseOrderBy reads the `orderBy` query param, which must be one of the `allowed` fields,
+// optionally prefixed with "-" for descending order. It returns `defaultField` if not given.
+func parseOrderBy(r *http.Request, defaultField string, allowed ...string) (string, bool, error) {
+	v := r.URL.Query().Get("orderBy")
+	if v == "" {
+		return defaultField, false, nil
+	}
+
+	field := strings.TrimPrefix(v, "-")
+	desc := field != v
+
+	for _, a := range allowed {
+		if field == a {
+			return field, desc, nil
+		}
+	}
+
+	return "", false, errs.NewBadRequest(fmt.Sprintf("invalid orderBy, valid fields (optionally prefixed with '-' for descending order): %s", strings.Join(allowed, ", ")))
+}
@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/gkatanacio/marvel-characters-api/internal/logging"
+)
+
+// RequestIdHeader holds the ID of a request, which is generated unless the client sends one.
+const RequestIdHeader = "X-Request-ID"
+
+// validRequestId matches the request IDs sent by clients that are kept, others being replaced.
+var validRequestId = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+// LogRequests returns a middleware giving each request an ID, which is echoed back in the
+// X-Request-ID header and added to every line logged while serving the request, and logging
+// each request served.
+func LogRequests(logger *logging.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id := r.Header.Get(RequestIdHeader)
+			if !validRequestId.MatchString(id) {
+				id = newRequestId()
+			}
+			w.Header().Set(RequestIdHeader, id)
+			r = r.WithContext(logging.WithRequestId(r.Context(), id))
+
+			start := time.Now()
+			rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(rec, r)
+
+			logger.Ctx(r.Context()).Info("request served",
+				"method", r.Method, "path", r.URL.Path, "status", rec.status, "duration", time.Since(start))
+		})
+	}
+}
+
+func newRequestId() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,67 @@
+package handlers_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gkatanacio/marvel-characters-api/internal/handlers"
+	"github.com/gkatanacio/marvel-characters-api/internal/logging"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_LogRequests(t *testing.T) {
+	tests := map[string]struct {
+		requestId  string
+		propagated bool
+	}{
+		"generated when missing": {
+			requestId: "",
+		},
+		"propagated": {
+			requestId:  "4f2b-client-id",
+			propagated: true,
+		},
+		"replaced when invalid": {
+			requestId: "bad id\nwith newline",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			// given
+			var buf bytes.Buffer
+			logger := logging.New(&buf, logging.LevelInfo)
+
+			var seenId string
+			handler := handlers.LogRequests(logger)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				seenId = logging.RequestId(r.Context())
+				w.WriteHeader(http.StatusTeapot)
+			}))
+
+			rr := httptest.NewRecorder()
+
+			req, _ := http.NewRequest(http.MethodGet, "/characters", nil)
+			if test.requestId != "" {
+				req.Header.Set(handlers.RequestIdHeader, test.requestId)
+			}
+
+			// when
+			handler.ServeHTTP(rr, req)
+
+			// then
+			id := rr.Header().Get(handlers.RequestIdHeader)
+			assert.NotEmpty(t, id)
+			assert.Equal(t, id, seenId)
+			if test.propagated {
+				assert.Equal(t, test.requestId, id)
+			} else {
+				assert.NotEqual(t, test.requestId, id)
+			}
+			assert.Contains(t, buf.String(), `"msg":"request served"`)
+			assert.Contains(t, buf.String(), `"requestId":"`+id+`"`)
+			assert.Contains(t, buf.String(), `"status":418`)
+		})
+	}
+}
@@ -0,0 +1,623 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gkatanacio/marvel-characters-api/internal/errs"
+	"github.com/gkatanacio/marvel-characters-api/internal/logging"
+	"github.com/gkatanacio/marvel-characters-api/internal/marvel"
+	"github.com/gorilla/mux"
+)
+
+type GetComicsHandler struct {
+	marvelService marvel.Servicer
+	logger        *logging.Logger
+}
+
+func NewGetComicsHandler(marvelService marvel.Servicer, logger *logging.Logger) *GetComicsHandler {
+	return &GetComicsHandler{marvelService, logger}
+}
+
+// GetComics godoc
+// @summary Get a page of Comics
+// @tags Comics
+// @produce json
+// @param limit query int false "Maximum number of comics to return (1 to 100, default 20)"
+// @param offset query int false "Number of comics to skip"
+// @success 200 {array} marvel.Comic
+// @router /comics [get]
+func (h *GetComicsHandler) Handle(w http.ResponseWriter, r *http.Request) {
+	p, query, err := parseResourceQuery(r)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	comics, total, err := h.marvelService.GetComics(r.Context(), query)
+	if err != nil {
+		logError(h.logger, r, err)
+		errorResponse(w, err)
+		return
+	}
+
+	jsonResponse(w, newPageResponseBody(r, p, total, len(comics), comics), http.StatusOK)
+}
+
+type GetComicHandler struct {
+	marvelService marvel.Servicer
+	logger        *logging.Logger
+}
+
+func NewGetComicHandler(marvelService marvel.Servicer, logger *logging.Logger) *GetComicHandler {
+	return &GetComicHandler{marvelService, logger}
+}
+
+// GetComic godoc
+// @summary Get Comic information
+// @tags Comics
+// @produce json
+// @param id path int true "Comic ID"
+// @success 200 {object} marvel.Comic
+// @router /comics/{id} [get]
+func (h *GetComicHandler) Handle(w http.ResponseWriter, r *http.Request) {
+	id, err := parseIdVar(r)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	comic, err := h.marvelService.GetComic(r.Context(), id)
+	if err != nil {
+		logError(h.logger, r, err)
+		errorResponse(w, err)
+		return
+	}
+
+	jsonResponse(w, comic, http.StatusOK)
+}
+
+type GetSeriesListHandler struct {
+	marvelService marvel.Servicer
+	logger        *logging.Logger
+}
+
+func NewGetSeriesListHandler(marvelService marvel.Servicer, logger *logging.Logger) *GetSeriesListHandler {
+	return &GetSeriesListHandler{marvelService, logger}
+}
+
+// GetSeriesList godoc
+// @summary Get a page of Series
+// @tags Series
+// @produce json
+// @param limit query int false "Maximum number of series to return (1 to 100, default 20)"
+// @param offset query int false "Number of series to skip"
+// @success 200 {array} marvel.Series
+// @router /series [get]
+func (h *GetSeriesListHandler) Handle(w http.ResponseWriter, r *http.Request) {
+	p, query, err := parseResourceQuery(r)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	seriesList, total, err := h.marvelService.GetSeriesList(r.Context(), query)
+	if err != nil {
+		logError(h.logger, r, err)
+		errorResponse(w, err)
+		return
+	}
+
+	jsonResponse(w, newPageResponseBody(r, p, total, len(seriesList), seriesList), http.StatusOK)
+}
+
+type GetSeriesHandler struct {
+	marvelService marvel.Servicer
+	logger        *logging.Logger
+}
+
+func NewGetSeriesHandler(marvelService marvel.Servicer, logger *logging.Logger) *GetSeriesHandler {
+	return &GetSeriesHandler{marvelService, logger}
+}
+
+// GetSeries godoc
+// @summary Get Series information
+// @tags Series
+// @produce json
+// @param id path int true "Series ID"
+// @success 200 {object} marvel.Series
+// @router /series/{id} [get]
+func (h *GetSeriesHandler) Handle(w http.ResponseWriter, r *http.Request) {
+	id, err := parseIdVar(r)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	series, err := h.marvelService.GetSeries(r.Context(), id)
+	if err != nil {
+		logError(h.logger, r, err)
+		errorResponse(w, err)
+		return
+	}
+
+	jsonResponse(w, series, http.StatusOK)
+}
+
+type GetEventsHandler struct {
+	marvelService marvel.Servicer
+	logger        *logging.Logger
+}
+
+func NewGetEventsHandler(marvelService marvel.Servicer, logger *logging.Logger) *GetEventsHandler {
+	return &GetEventsHandler{marvelService, logger}
+}
+
+// GetEvents godoc
+// @summary Get a page of Events
+// @tags Events
+// @produce json
+// @param limit query int false "Maximum number of events to return (1 to 100, default 20)"
+// @param offset query int false "Number of events to skip"
+// @success 200 {array} marvel.Event
+// @router /events [get]
+func (h *GetEventsHandler) Handle(w http.ResponseWriter, r *http.Request) {
+	p, query, err := parseResourceQuery(r)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	events, total, err := h.marvelService.GetEvents(r.Context(), query)
+	if err != nil {
+		logError(h.logger, r, err)
+		errorResponse(w, err)
+		return
+	}
+
+	jsonResponse(w, newPageResponseBody(r, p, total, len(events), events), http.StatusOK)
+}
+
+type GetEventHandler struct {
+	marvelService marvel.Servicer
+	logger        *logging.Logger
+}
+
+func NewGetEventHandler(marvelService marvel.Servicer, logger *logging.Logger) *GetEventHandler {
+	return &GetEventHandler{marvelService, logger}
+}
+
+// GetEvent godoc
+// @summary Get Event information
+// @tags Events
+// @produce json
+// @param id path int true "Event ID"
+// @success 200 {object} marvel.Event
+// @router /events/{id} [get]
+func (h *GetEventHandler) Handle(w http.ResponseWriter, r *http.Request) {
+	id, err := parseIdVar(r)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	event, err := h.marvelService.GetEvent(r.Context(), id)
+	if err != nil {
+		logError(h.logger, r, err)
+		errorResponse(w, err)
+		return
+	}
+
+	jsonResponse(w, event, http.StatusOK)
+}
+
+type GetStoriesHandler struct {
+	marvelService marvel.Servicer
+	logger        *logging.Logger
+}
+
+func NewGetStoriesHandler(marvelService marvel.Servicer, logger *logging.Logger) *GetStoriesHandler {
+	return &GetStoriesHandler{marvelService, logger}
+}
+
+// GetStories godoc
+// @summary Get a page of Stories
+// @tags Stories
+// @produce json
+// @param limit query int false "Maximum number of stories to return (1 to 100, default 20)"
+// @param offset query int false "Number of stories to skip"
+// @success 200 {array} marvel.Story
+// @router /stories [get]
+func (h *GetStoriesHandler) Handle(w http.ResponseWriter, r *http.Request) {
+	p, query, err := parseResourceQuery(r)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	stories, total, err := h.marvelService.GetStories(r.Context(), query)
+	if err != nil {
+		logError(h.logger, r, err)
+		errorResponse(w, err)
+		return
+	}
+
+	jsonResponse(w, newPageResponseBody(r, p, total, len(stories), stories), http.StatusOK)
+}
+
+type GetStoryHandler struct {
+	marvelService marvel.Servicer
+	logger        *logging.Logger
+}
+
+func NewGetStoryHandler(marvelService marvel.Servicer, logger *logging.Logger) *GetStoryHandler {
+	return &GetStoryHandler{marvelService, logger}
+}
+
+// GetStory godoc
+// @summary Get Story information
+// @tags Stories
+// @produce json
+// @param id path int true "Story ID"
+// @success 200 {object} marvel.Story
+// @router /stories/{id} [get]
+func (h *GetStoryHandler) Handle(w http.ResponseWriter, r *http.Request) {
+	id, err := parseIdVar(r)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	story, err := h.marvelService.GetStory(r.Context(), id)
+	if err != nil {
+		logError(h.logger, r, err)
+		errorResponse(w, err)
+		return
+	}
+
+	jsonResponse(w, story, http.StatusOK)
+}
+
+type GetCreatorsHandler struct {
+	marvelService marvel.Servicer
+	logger        *logging.Logger
+}
+
+func NewGetCreatorsHandler(marvelService marvel.Servicer, logger *logging.Logger) *GetCreatorsHandler {
+	return &GetCreatorsHandler{marvelService, logger}
+}
+
+// GetCreators godoc
+// @summary Get a page of Creators
+// @tags Creators
+// @produce json
+// @param limit query int false "Maximum number of creators to return (1 to 100, default 20)"
+// @param offset query int false "Number of creators to skip"
+// @success 200 {array} marvel.Creator
+// @router /creators [get]
+func (h *GetCreatorsHandler) Handle(w http.ResponseWriter, r *http.Request) {
+	p, query, err := parseResourceQuery(r)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	creators, total, err := h.marvelService.GetCreators(r.Context(), query)
+	if err != nil {
+		logError(h.logger, r, err)
+		errorResponse(w, err)
+		return
+	}
+
+	jsonResponse(w, newPageResponseBody(r, p, total, len(creators), creators), http.StatusOK)
+}
+
+type GetCreatorHandler struct {
+	marvelService marvel.Servicer
+	logger        *logging.Logger
+}
+
+func NewGetCreatorHandler(marvelService marvel.Servicer, logger *logging.Logger) *GetCreatorHandler {
+	return &GetCreatorHandler{marvelService, logger}
+}
+
+// GetCreator godoc
+// @summary Get Creator information
+// @tags Creators
+// @produce json
+// @param id path int true "Creator ID"
+// @success 200 {object} marvel.Creator
+// @router /creators/{id} [get]
+func (h *GetCreatorHandler) Handle(w http.ResponseWriter, r *http.Request) {
+	id, err := parseIdVar(r)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	creator, err := h.marvelService.GetCreator(r.Context(), id)
+	if err != nil {
+		logError(h.logger, r, err)
+		errorResponse(w, err)
+		return
+	}
+
+	jsonResponse(w, creator, http.StatusOK)
+}
+
+type GetCharacterComicsHandler struct {
+	marvelService marvel.Servicer
+	logger        *logging.Logger
+}
+
+func NewGetCharacterComicsHandler(marvelService marvel.Servicer, logger *logging.Logger) *GetCharacterComicsHandler {
+	return &GetCharacterComicsHandler{marvelService, logger}
+}
+
+// GetCharacterComics godoc
+// @summary Get a page of the Comics a Character appears in
+// @tags Characters
+// @produce json
+// @param id path int true "Character ID"
+// @param limit query int false "Maximum number of comics to return (1 to 100, default 20)"
+// @param offset query int false "Number of comics to skip"
+// @param orderBy query string false "Order of the comics (focDate, onsaleDate, title, issueNumber, modified, optionally prefixed with '-' for descending order)"
+// @param format query string false "Format of the comics (comic, magazine, trade paperback, hardcover, digest, graphic novel, digital comic, infinite comic)"
+// @param dateRange query string false "Range of release dates of the comics (YYYY-MM-DD,YYYY-MM-DD)"
+// @success 200 {array} marvel.Comic
+// @router /characters/{id}/comics [get]
+func (h *GetCharacterComicsHandler) Handle(w http.ResponseWriter, r *http.Request) {
+	charId, err := parseIdVar(r)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	p, query, err := parseCharacterRelatedQuery(r, comicOrderFields, true)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	comics, total, err := h.marvelService.GetCharacterComics(r.Context(), charId, query)
+	if err != nil {
+		logError(h.logger, r, err)
+		errorResponse(w, err)
+		return
+	}
+
+	jsonResponse(w, newPageResponseBody(r, p, total, len(comics), comics), http.StatusOK)
+}
+
+type GetCharacterSeriesHandler struct {
+	marvelService marvel.Servicer
+	logger        *logging.Logger
+}
+
+func NewGetCharacterSeriesHandler(marvelService marvel.Servicer, logger *logging.Logger) *GetCharacterSeriesHandler {
+	return &GetCharacterSeriesHandler{marvelService, logger}
+}
+
+// GetCharacterSeries godoc
+// @summary Get a page of the Series a Character appears in
+// @tags Characters
+// @produce json
+// @param id path int true "Character ID"
+// @param limit query int false "Maximum number of series to return (1 to 100, default 20)"
+// @param offset query int false "Number of series to skip"
+// @param orderBy query string false "Order of the series (title, modified, startYear, optionally prefixed with '-' for descending order)"
+// @success 200 {array} marvel.Series
+// @router /characters/{id}/series [get]
+func (h *GetCharacterSeriesHandler) Handle(w http.ResponseWriter, r *http.Request) {
+	charId, err := parseIdVar(r)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	p, query, err := parseCharacterRelatedQuery(r, seriesOrderFields, false)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	seriesList, total, err := h.marvelService.GetCharacterSeries(r.Context(), charId, query)
+	if err != nil {
+		logError(h.logger, r, err)
+		errorResponse(w, err)
+		return
+	}
+
+	jsonResponse(w, newPageResponseBody(r, p, total, len(seriesList), seriesList), http.StatusOK)
+}
+
+type GetCharacterEventsHandler struct {
+	marvelService marvel.Servicer
+	logger        *logging.Logger
+}
+
+func NewGetCharacterEventsHandler(marvelService marvel.Servicer, logger *logging.Logger) *GetCharacterEventsHandler {
+	return &GetCharacterEventsHandler{marvelService, logger}
+}
+
+// GetCharacterEvents godoc
+// @summary Get a page of the Events a Character appears in
+// @tags Characters
+// @produce json
+// @param id path int true "Character ID"
+// @param limit query int false "Maximum number of events to return (1 to 100, default 20)"
+// @param offset query int false "Number of events to skip"
+// @param orderBy query string false "Order of the events (name, startDate, modified, optionally prefixed with '-' for descending order)"
+// @success 200 {array} marvel.Event
+// @router /characters/{id}/events [get]
+func (h *GetCharacterEventsHandler) Handle(w http.ResponseWriter, r *http.Request) {
+	charId, err := parseIdVar(r)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	p, query, err := parseCharacterRelatedQuery(r, eventOrderFields, false)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	events, total, err := h.marvelService.GetCharacterEvents(r.Context(), charId, query)
+	if err != nil {
+		logError(h.logger, r, err)
+		errorResponse(w, err)
+		return
+	}
+
+	jsonResponse(w, newPageResponseBody(r, p, total, len(events), events), http.StatusOK)
+}
+
+type GetCharacterStoriesHandler struct {
+	marvelService marvel.Servicer
+	logger        *logging.Logger
+}
+
+func NewGetCharacterStoriesHandler(marvelService marvel.Servicer, logger *logging.Logger) *GetCharacterStoriesHandler {
+	return &GetCharacterStoriesHandler{marvelService, logger}
+}
+
+// GetCharacterStories godoc
+// @summary Get a page of the Stories a Character appears in
+// @tags Characters
+// @produce json
+// @param id path int true "Character ID"
+// @param limit query int false "Maximum number of stories to return (1 to 100, default 20)"
+// @param offset query int false "Number of stories to skip"
+// @param orderBy query string false "Order of the stories (id, modified, optionally prefixed with '-' for descending order)"
+// @success 200 {array} marvel.Story
+// @router /characters/{id}/stories [get]
+func (h *GetCharacterStoriesHandler) Handle(w http.ResponseWriter, r *http.Request) {
+	charId, err := parseIdVar(r)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	p, query, err := parseCharacterRelatedQuery(r, storyOrderFields, false)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	stories, total, err := h.marvelService.GetCharacterStories(r.Context(), charId, query)
+	if err != nil {
+		logError(h.logger, r, err)
+		errorResponse(w, err)
+		return
+	}
+
+	jsonResponse(w, newPageResponseBody(r, p, total, len(stories), stories), http.StatusOK)
+}
+
+// The fields that the resources related to a character can be sorted by (see Marvel's API docs).
+var (
+	comicOrderFields  = []string{"focDate", "onsaleDate", "title", "issueNumber", "modified"}
+	seriesOrderFields = []string{"title", "modified", "startYear"}
+	eventOrderFields  = []string{"name", "startDate", "modified"}
+	storyOrderFields  = []string{"id", "modified"}
+)
+
+// comicFormats are the formats comics can be filtered by.
+var comicFormats = []string{"comic", "magazine", "trade paperback", "hardcover", "digest", "graphic novel", "digital comic", "infinite comic"}
+
+// parseCharacterRelatedQuery reads the pagination, `orderBy` and (if `comicFilters` is true)
+// `format` and `dateRange` query params of a request listing the resources related to a character.
+func parseCharacterRelatedQuery(r *http.Request, orderByFields []string, comicFilters bool) (*page, marvel.ResourceQuery, error) {
+	p, query, err := parseResourceQuery(r)
+	if err != nil {
+		return nil, query, err
+	}
+
+	field, desc, err := parseOrderBy(r, "", orderByFields...)
+	if err != nil {
+		return nil, query, err
+	}
+	if field != "" && desc {
+		query.OrderBy = "-" + field
+	} else {
+		query.OrderBy = field
+	}
+
+	if !comicFilters {
+		return p, query, nil
+	}
+
+	if format := r.URL.Query().Get("format"); format != "" {
+		if !contains(comicFormats, format) {
+			return nil, query, errs.NewBadRequest(fmt.Sprintf("invalid format, valid formats: %s", strings.Join(comicFormats, ", ")))
+		}
+		query.Format = format
+	}
+
+	if dateRange := r.URL.Query().Get("dateRange"); dateRange != "" {
+		if !validDateRange(dateRange) {
+			return nil, query, errs.NewBadRequest("dateRange must be two dates in YYYY-MM-DD format separated by a comma, the first not after the second")
+		}
+		query.DateRange = dateRange
+	}
+
+	return p, query, nil
+}
+
+func validDateRange(dateRange string) bool {
+	dates := strings.Split(dateRange, ",")
+	if len(dates) != 2 {
+		return false
+	}
+
+	start, err := time.Parse("2006-01-02", dates[0])
+	if err != nil {
+		return false
+	}
+	end, err := time.Parse("2006-01-02", dates[1])
+	if err != nil {
+		return false
+	}
+
+	return !start.After(end)
+}
+
+func contains(values []string, v string) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
+
+// parseResourceQuery reads the pagination query params of a request listing resources.
+func parseResourceQuery(r *http.Request) (*page, marvel.ResourceQuery, error) {
+	p, err := parsePage(r)
+	if err != nil {
+		return nil, marvel.ResourceQuery{}, err
+	}
+	if p == nil {
+		p = &page{limit: defaultPageLimit}
+	}
+
+	return p, marvel.ResourceQuery{Limit: p.limit, Offset: p.offset}, nil
+}
+
+// parseIdVar reads the `id` path variable of the request.
+func parseIdVar(r *http.Request) (int, error) {
+	return parseIntVar(r, "id")
+}
+
+// parseIntVar reads the integer path variable `name` of the request.
+func parseIntVar(r *http.Request, name string) (int, error) {
+	v, err := strconv.Atoi(mux.Vars(r)[name])
+	if err != nil {
+		return 0, errs.NewBadRequest("invalid " + name)
+	}
+	return v, nil
+}
@@ -0,0 +1,179 @@
+package handlers_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gkatanacio/marvel-characters-api/internal/errs"
+	"github.com/gkatanacio/marvel-characters-api/internal/handlers"
+	"github.com/gkatanacio/marvel-characters-api/internal/logging"
+	"github.com/gkatanacio/marvel-characters-api/internal/marvel"
+	"github.com/gkatanacio/marvel-characters-api/mocks"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_GetComicsHandler_Handle_HappyPath(t *testing.T) {
+	// given
+	marvelServiceMock := new(mocks.Servicer)
+	marvelServiceMock.On("GetComics", mock.Anything, marvel.ResourceQuery{Limit: 1, Offset: 0}).Return([]*marvel.Comic{
+		{Id: 41112, Title: "5 Ronin (Marvel Edition) (2011) #2", IssueNumber: 2, PageCount: 32},
+	}, 48000, nil)
+
+	handler := handlers.NewGetComicsHandler(marvelServiceMock, logging.Discard())
+
+	rr := httptest.NewRecorder()
+
+	req, _ := http.NewRequest(http.MethodGet, "/comics?limit=1", nil)
+
+	// when
+	handler.Handle(rr, req)
+
+	// then
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{
+		"total": 48000,
+		"count": 1,
+		"limit": 1,
+		"offset": 0,
+		"links": {"next": "/comics?limit=1&offset=1"},
+		"results": [{"id": 41112, "title": "5 Ronin (Marvel Edition) (2011) #2", "issueNumber": 2, "description": "", "pageCount": 32}]
+	}`, rr.Body.String())
+	marvelServiceMock.AssertExpectations(t)
+}
+
+func Test_GetSeriesHandler_Handle_HappyPath(t *testing.T) {
+	// given
+	marvelServiceMock := new(mocks.Servicer)
+	marvelServiceMock.On("GetSeries", mock.Anything, 2069).Return(&marvel.Series{
+		Id:        2069,
+		Title:     "Hulk (1999 - 2008)",
+		StartYear: 1999,
+		EndYear:   2008,
+	}, nil)
+
+	handler := handlers.NewGetSeriesHandler(marvelServiceMock, logging.Discard())
+
+	rr := httptest.NewRecorder()
+
+	req, _ := http.NewRequest(http.MethodGet, "/series/2069", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "2069"})
+
+	// when
+	handler.Handle(rr, req)
+
+	// then
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"id": 2069, "title": "Hulk (1999 - 2008)", "description": "", "startYear": 1999, "endYear": 2008}`, rr.Body.String())
+	marvelServiceMock.AssertExpectations(t)
+}
+
+func Test_GetCreatorHandler_Handle_NotFound(t *testing.T) {
+	// given
+	marvelServiceMock := new(mocks.Servicer)
+	marvelServiceMock.On("GetCreator", mock.Anything, 1).Return(nil, errs.NewNotFound("no results"))
+
+	handler := handlers.NewGetCreatorHandler(marvelServiceMock, logging.Discard())
+
+	rr := httptest.NewRecorder()
+
+	req, _ := http.NewRequest(http.MethodGet, "/creators/1", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "1"})
+
+	// when
+	handler.Handle(rr, req)
+
+	// then
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	marvelServiceMock.AssertExpectations(t)
+}
+
+func Test_GetStoryHandler_Handle_InvalidId(t *testing.T) {
+	// given
+	handler := handlers.NewGetStoryHandler(nil, logging.Discard())
+
+	rr := httptest.NewRecorder()
+
+	req, _ := http.NewRequest(http.MethodGet, "/stories/abc", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "abc"})
+
+	// when
+	handler.Handle(rr, req)
+
+	// then
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func Test_GetCharacterComicsHandler_Handle_HappyPath(t *testing.T) {
+	// given
+	marvelServiceMock := new(mocks.Servicer)
+	marvelServiceMock.On("GetCharacterComics", mock.Anything, 1009351, marvel.ResourceQuery{
+		Limit:     20,
+		OrderBy:   "-onsaleDate",
+		Format:    "graphic novel",
+		DateRange: "2010-01-01,2012-12-31",
+	}).Return([]*marvel.Comic{
+		{Id: 41112, Title: "5 Ronin (Marvel Edition) (2011) #2"},
+	}, 1, nil)
+
+	handler := handlers.NewGetCharacterComicsHandler(marvelServiceMock, logging.Discard())
+
+	rr := httptest.NewRecorder()
+
+	req, _ := http.NewRequest(http.MethodGet, "/characters/1009351/comics?orderBy=-onsaleDate&format=graphic+novel&dateRange=2010-01-01,2012-12-31", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "1009351"})
+
+	// when
+	handler.Handle(rr, req)
+
+	// then
+	assert.Equal(t, http.StatusOK, rr.Code)
+	marvelServiceMock.AssertExpectations(t)
+}
+
+func Test_GetCharacterComicsHandler_Handle_InvalidFilters(t *testing.T) {
+	// given
+	handler := handlers.NewGetCharacterComicsHandler(nil, logging.Discard())
+
+	for _, query := range []string{
+		"orderBy=name",
+		"format=pamphlet",
+		"dateRange=2012-01-01",
+		"dateRange=2012-12-31,2010-01-01",
+		"dateRange=2012-01-01,yesterday",
+		"limit=1000",
+	} {
+		rr := httptest.NewRecorder()
+
+		req, _ := http.NewRequest(http.MethodGet, "/characters/1009351/comics?"+query, nil)
+		req = mux.SetURLVars(req, map[string]string{"id": "1009351"})
+
+		// when
+		handler.Handle(rr, req)
+
+		// then
+		assert.Equal(t, http.StatusBadRequest, rr.Code, query)
+	}
+}
+
+func Test_GetCharacterEventsHandler_Handle_NotFound(t *testing.T) {
+	// given
+	marvelServiceMock := new(mocks.Servicer)
+	marvelServiceMock.On("GetCharacterEvents", mock.Anything, 1, marvel.ResourceQuery{Limit: 20, OrderBy: "startDate"}).Return(nil, 0, errs.NewNotFound("no results"))
+
+	handler := handlers.NewGetCharacterEventsHandler(marvelServiceMock, logging.Discard())
+
+	rr := httptest.NewRecorder()
+
+	req, _ := http.NewRequest(http.MethodGet, "/characters/1/events?orderBy=startDate", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "1"})
+
+	// when
+	handler.Handle(rr, req)
+
+	// then
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	marvelServiceMock.AssertExpectations(t)
+}
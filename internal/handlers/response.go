@@ -5,6 +5,7 @@ import (
 	"net/http"
 
 	"github.com/gkatanacio/marvel-characters-api/internal/errs"
+	"github.com/gkatanacio/marvel-characters-api/internal/logging"
 )
 
 func jsonResponse(w http.ResponseWriter, data interface{}, statusCode int) {
@@ -32,3 +33,14 @@ func errorResponse(w http.ResponseWriter, err error) {
 
 	jsonResponse(w, body, status)
 }
+
+// logError logs the error a request failed with: as a warning if the request was at fault
+// (e.g. an unknown ID), as an error otherwise.
+func logError(logger *logging.Logger, r *http.Request, err error) {
+	logger = logger.Ctx(r.Context())
+	if e, ok := err.(errs.HttpError); ok && e.StatusCode() < http.StatusInternalServerError {
+		logger.Warn("request failed", "error", err)
+		return
+	}
+	logger.Error("request failed", "error", err)
+}
@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gkatanacio/marvel-characters-api/internal/logging"
+	"github.com/gkatanacio/marvel-characters-api/internal/marvel"
+)
+
+type StreamChangesHandler struct {
+	marvelService     marvel.Servicer
+	heartbeatInterval time.Duration
+	logger            *logging.Logger
+}
+
+func NewStreamChangesHandler(marvelService marvel.Servicer, heartbeatInterval time.Duration, logger *logging.Logger) *StreamChangesHandler {
+	return &StreamChangesHandler{marvelService, heartbeatInterval, logger}
+}
+
+// StreamChanges godoc
+// @summary Stream the changes to the Characters as they are found
+// @description A Server-Sent Events stream with an event for each character added, modified or removed
+// @description by the syncs with Marvel's API (the data of each event is a change, as listed by /characters/changes).
+// @description The ID of each event can be sent back in the Last-Event-ID header when reconnecting,
+// @description to get the changes missed in between. Streams falling too far behind are closed.
+// @tags Characters
+// @produce text/event-stream
+// @param Last-Event-ID header string false "ID of the last event received, to resume from"
+// @success 200 {object} marvel.Change
+// @router /characters/stream [get]
+func (h *StreamChangesHandler) Handle(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		errorResponse(w, errors.New("streaming not supported"))
+		return
+	}
+
+	sub, err := h.marvelService.SubscribeChanges(r.Header.Get("Last-Event-ID"))
+	if err != nil {
+		logError(h.logger, r, err)
+		errorResponse(w, err)
+		return
+	}
+	defer sub.Close()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+
+	for _, e := range sub.Backlog {
+		if err := writeEvent(w, e); err != nil {
+			return
+		}
+	}
+	flusher.Flush()
+
+	var heartbeats <-chan time.Time
+	if h.heartbeatInterval > 0 {
+		ticker := time.NewTicker(h.heartbeatInterval)
+		defer ticker.Stop()
+		heartbeats = ticker.C
+	}
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case e, ok := <-sub.Events:
+			if !ok {
+				h.logger.Ctx(r.Context()).Info("change stream closed")
+				return
+			}
+			if err := writeEvent(w, e); err != nil {
+				return
+			}
+		case <-heartbeats:
+			if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+				return
+			}
+		}
+		flusher.Flush()
+	}
+}
+
+func writeEvent(w http.ResponseWriter, e *marvel.ChangeEvent) error {
+	data, err := json.Marshal(e.Change)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %s\ndata: %s\n\n", e.Id, data)
+	return err
+}
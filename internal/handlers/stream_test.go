@@ -0,0 +1,72 @@
+package handlers_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gkatanacio/marvel-characters-api/internal/errs"
+	"github.com/gkatanacio/marvel-characters-api/internal/handlers"
+	"github.com/gkatanacio/marvel-characters-api/internal/logging"
+	"github.com/gkatanacio/marvel-characters-api/internal/marvel"
+	"github.com/gkatanacio/marvel-characters-api/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_StreamChangesHandler_Handle_HappyPath(t *testing.T) {
+	// given
+	t0 := time.Date(2021, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	changeLog := marvel.NewChangeLog(0)
+	changeLog.Record(nil, t0)
+	page, _ := changeLog.Changes(marvel.ChangeQuery{Since: t0})
+	changeLog.Record([]*marvel.Change{{Id: 1009351, Type: marvel.ChangeRemoved}}, t0.Add(time.Minute))
+	sub, _ := changeLog.Subscribe(page.Cursor, 10)
+	changeLog.Record([]*marvel.Change{{Id: 1009610, Type: marvel.ChangeAdded}}, t0.Add(2*time.Minute))
+	changeLog.CloseSubscriptions()
+
+	marvelServiceMock := new(mocks.Servicer)
+	marvelServiceMock.On("SubscribeChanges", page.Cursor).Return(sub, nil)
+
+	handler := handlers.NewStreamChangesHandler(marvelServiceMock, time.Minute, logging.Discard())
+
+	rr := httptest.NewRecorder()
+
+	req, _ := http.NewRequest(http.MethodGet, "/characters/stream", nil)
+	req.Header.Set("Last-Event-ID", page.Cursor)
+
+	// when
+	handler.Handle(rr, req)
+
+	// then
+	epoch := strings.Split(page.Cursor, ".")[0]
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "text/event-stream", rr.Header().Get("Content-Type"))
+	assert.Equal(t, "id: "+epoch+".1\n"+
+		`data: {"id":1009351,"type":"removed","time":"2021-03-01T10:01:00Z"}`+"\n\n"+
+		"id: "+epoch+".2\n"+
+		`data: {"id":1009610,"type":"added","time":"2021-03-01T10:02:00Z"}`+"\n\n", rr.Body.String())
+	marvelServiceMock.AssertExpectations(t)
+}
+
+func Test_StreamChangesHandler_Handle_ChangesUnavailable(t *testing.T) {
+	// given
+	marvelServiceMock := new(mocks.Servicer)
+	marvelServiceMock.On("SubscribeChanges", "1.1").Return(nil, errs.NewGone("changes after the cursor are no longer available"))
+
+	handler := handlers.NewStreamChangesHandler(marvelServiceMock, time.Minute, logging.Discard())
+
+	rr := httptest.NewRecorder()
+
+	req, _ := http.NewRequest(http.MethodGet, "/characters/stream", nil)
+	req.Header.Set("Last-Event-ID", "1.1")
+
+	// when
+	handler.Handle(rr, req)
+
+	// then
+	assert.Equal(t, http.StatusGone, rr.Code)
+	marvelServiceMock.AssertExpectations(t)
+}
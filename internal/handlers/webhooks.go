@@ -0,0 +1,179 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/gkatanacio/marvel-characters-api/internal/errs"
+	"github.com/gkatanacio/marvel-characters-api/internal/logging"
+	"github.com/gkatanacio/marvel-characters-api/internal/marvel"
+	"github.com/gorilla/mux"
+)
+
+// RequireToken wraps `handle` so that only requests with the bearer token `token`
+// in their Authorization header are served.
+func RequireToken(token string, handle http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		given := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+			errorResponse(w, errs.NewUnauthorized("invalid or missing admin token"))
+			return
+		}
+		handle(w, r)
+	}
+}
+
+type GetWebhooksHandler struct {
+	webhooks marvel.WebhookManager
+}
+
+func NewGetWebhooksHandler(webhooks marvel.WebhookManager) *GetWebhooksHandler {
+	return &GetWebhooksHandler{webhooks}
+}
+
+// GetWebhooks godoc
+// @summary Get the registered webhooks
+// @tags Admin
+// @produce json
+// @param Authorization header string true "Bearer admin token"
+// @success 200 {array} marvel.Webhook
+// @router /admin/webhooks [get]
+func (h *GetWebhooksHandler) Handle(w http.ResponseWriter, r *http.Request) {
+	jsonResponse(w, h.webhooks.Webhooks(), http.StatusOK)
+}
+
+type RegisterWebhookHandler struct {
+	webhooks marvel.WebhookManager
+	logger   *logging.Logger
+}
+
+func NewRegisterWebhookHandler(webhooks marvel.WebhookManager, logger *logging.Logger) *RegisterWebhookHandler {
+	return &RegisterWebhookHandler{webhooks, logger}
+}
+
+// RegisterWebhook godoc
+// @summary Register a webhook
+// @description The webhook is sent a payload describing the changes found by each sync with Marvel's API from now on.
+// @description Payloads are signed with the secret of the webhook (only returned here): the X-Webhook-Signature header
+// @description holds sha256= followed by the hex-encoded HMAC-SHA256 of the X-Webhook-Timestamp header, a dot and the body.
+// @tags Admin
+// @accept json
+// @produce json
+// @param Authorization header string true "Bearer admin token"
+// @param webhook body marvel.WebhookRegistration true "Webhook to register (a random secret is generated if none is given)"
+// @success 201 {object} marvel.Webhook
+// @router /admin/webhooks [post]
+func (h *RegisterWebhookHandler) Handle(w http.ResponseWriter, r *http.Request) {
+	var reg marvel.WebhookRegistration
+	if err := json.NewDecoder(r.Body).Decode(&reg); err != nil {
+		errorResponse(w, errs.NewBadRequest("invalid webhook registration"))
+		return
+	}
+
+	webhook, err := h.webhooks.Register(reg)
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	h.logger.Ctx(r.Context()).Info("webhook registered", "webhook", webhook.Id, "url", logging.RedactUrl(webhook.Url))
+	jsonResponse(w, webhook, http.StatusCreated)
+}
+
+type UnregisterWebhookHandler struct {
+	webhooks marvel.WebhookManager
+}
+
+func NewUnregisterWebhookHandler(webhooks marvel.WebhookManager) *UnregisterWebhookHandler {
+	return &UnregisterWebhookHandler{webhooks}
+}
+
+// UnregisterWebhook godoc
+// @summary Unregister a webhook
+// @tags Admin
+// @param Authorization header string true "Bearer admin token"
+// @param id path string true "Webhook ID"
+// @success 204
+// @router /admin/webhooks/{id} [delete]
+func (h *UnregisterWebhookHandler) Handle(w http.ResponseWriter, r *http.Request) {
+	if err := h.webhooks.Unregister(mux.Vars(r)["id"]); err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+type GetWebhookDeliveriesHandler struct {
+	webhooks marvel.WebhookManager
+}
+
+func NewGetWebhookDeliveriesHandler(webhooks marvel.WebhookManager) *GetWebhookDeliveriesHandler {
+	return &GetWebhookDeliveriesHandler{webhooks}
+}
+
+// GetWebhookDeliveries godoc
+// @summary Get the most recent deliveries to a webhook
+// @description Deliveries are listed from the newest to the oldest, each with its attempts.
+// @tags Admin
+// @produce json
+// @param Authorization header string true "Bearer admin token"
+// @param id path string true "Webhook ID"
+// @success 200 {array} marvel.WebhookDelivery
+// @router /admin/webhooks/{id}/deliveries [get]
+func (h *GetWebhookDeliveriesHandler) Handle(w http.ResponseWriter, r *http.Request) {
+	deliveries, err := h.webhooks.Deliveries(mux.Vars(r)["id"])
+	if err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	jsonResponse(w, deliveries, http.StatusOK)
+}
+
+type GetDeadLettersHandler struct {
+	webhooks marvel.WebhookManager
+}
+
+func NewGetDeadLettersHandler(webhooks marvel.WebhookManager) *GetDeadLettersHandler {
+	return &GetDeadLettersHandler{webhooks}
+}
+
+// GetDeadLetters godoc
+// @summary Get the most recent failed webhook deliveries
+// @description Deliveries whose attempts all failed are listed from the newest to the oldest.
+// @tags Admin
+// @produce json
+// @param Authorization header string true "Bearer admin token"
+// @success 200 {array} marvel.WebhookDelivery
+// @router /admin/webhooks/dead-letters [get]
+func (h *GetDeadLettersHandler) Handle(w http.ResponseWriter, r *http.Request) {
+	jsonResponse(w, h.webhooks.DeadLetters(), http.StatusOK)
+}
+
+type RetryDeadLetterHandler struct {
+	webhooks marvel.WebhookManager
+}
+
+func NewRetryDeadLetterHandler(webhooks marvel.WebhookManager) *RetryDeadLetterHandler {
+	return &RetryDeadLetterHandler{webhooks}
+}
+
+// RetryDeadLetter godoc
+// @summary Retry a failed webhook delivery
+// @description The delivery is taken off the dead-letter list and attempted again in the background.
+// @tags Admin
+// @param Authorization header string true "Bearer admin token"
+// @param id path string true "Delivery ID"
+// @success 202
+// @router /admin/webhooks/dead-letters/{id}/retry [post]
+func (h *RetryDeadLetterHandler) Handle(w http.ResponseWriter, r *http.Request) {
+	if err := h.webhooks.RetryDeadLetter(mux.Vars(r)["id"]); err != nil {
+		errorResponse(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
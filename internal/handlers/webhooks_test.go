@@ -0,0 +1,104 @@
+package handlers_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gkatanacio/marvel-characters-api/internal/errs"
+	"github.com/gkatanacio/marvel-characters-api/internal/handlers"
+	"github.com/gkatanacio/marvel-characters-api/internal/logging"
+	"github.com/gkatanacio/marvel-characters-api/internal/marvel"
+	"github.com/gkatanacio/marvel-characters-api/mocks"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_RequireToken_Unauthorized(t *testing.T) {
+	// given
+	handler := handlers.RequireToken("t0k3n", handlers.NewGetWebhooksHandler(nil).Handle)
+
+	for _, authorization := range []string{"", "Bearer wrong", "t0k3n2"} {
+		rr := httptest.NewRecorder()
+
+		req, _ := http.NewRequest(http.MethodGet, "/admin/webhooks", nil)
+		req.Header.Set("Authorization", authorization)
+
+		// when
+		handler(rr, req)
+
+		// then
+		assert.Equal(t, http.StatusUnauthorized, rr.Code, authorization)
+	}
+}
+
+func Test_RegisterWebhookHandler_Handle_HappyPath(t *testing.T) {
+	// given
+	createdAt := time.Date(2021, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	webhooksMock := new(mocks.WebhookManager)
+	webhooksMock.On("Register", marvel.WebhookRegistration{Url: "https://example.com/hook"}).Return(&marvel.Webhook{
+		Id:        "a1b2c3d4e5f60718",
+		Url:       "https://example.com/hook",
+		Secret:    "s3cr3t",
+		CreatedAt: createdAt,
+	}, nil)
+
+	handler := handlers.RequireToken("t0k3n", handlers.NewRegisterWebhookHandler(webhooksMock, logging.Discard()).Handle)
+
+	rr := httptest.NewRecorder()
+
+	req, _ := http.NewRequest(http.MethodPost, "/admin/webhooks", strings.NewReader(`{"url": "https://example.com/hook"}`))
+	req.Header.Set("Authorization", "Bearer t0k3n")
+
+	// when
+	handler(rr, req)
+
+	// then
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	assert.JSONEq(t, `{
+		"id": "a1b2c3d4e5f60718",
+		"url": "https://example.com/hook",
+		"secret": "s3cr3t",
+		"fromConfig": false,
+		"createdAt": "2021-03-01T10:00:00Z"
+	}`, rr.Body.String())
+	webhooksMock.AssertExpectations(t)
+}
+
+func Test_RegisterWebhookHandler_Handle_InvalidBody(t *testing.T) {
+	// given
+	handler := handlers.NewRegisterWebhookHandler(nil, logging.Discard())
+
+	rr := httptest.NewRecorder()
+
+	req, _ := http.NewRequest(http.MethodPost, "/admin/webhooks", strings.NewReader(`{"url": `))
+
+	// when
+	handler.Handle(rr, req)
+
+	// then
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func Test_UnregisterWebhookHandler_Handle_NotFound(t *testing.T) {
+	// given
+	webhooksMock := new(mocks.WebhookManager)
+	webhooksMock.On("Unregister", "unknown").Return(errs.NewNotFound("webhook not found"))
+
+	handler := handlers.NewUnregisterWebhookHandler(webhooksMock)
+
+	rr := httptest.NewRecorder()
+
+	req, _ := http.NewRequest(http.MethodDelete, "/admin/webhooks/unknown", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "unknown"})
+
+	// when
+	handler.Handle(rr, req)
+
+	// then
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	webhooksMock.AssertExpectations(t)
+}
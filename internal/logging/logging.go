@@ -0,0 +1,200 @@
+// Package logging implements a levelled logger writing one JSON object per line.
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Level is the severity of a log line.
+type Level int
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+var levelNames = []string{"debug", "info", "warn", "error"}
+
+func (l Level) String() string {
+	if l < LevelDebug || l > LevelError {
+		return fmt.Sprintf("level(%d)", int(l))
+	}
+	return levelNames[l]
+}
+
+// ParseLevel returns the level with the given name (debug, info, warn or error).
+func ParseLevel(name string) (Level, error) {
+	for i, n := range levelNames {
+		if strings.EqualFold(name, n) {
+			return Level(i), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown log level %q", name)
+}
+
+// output is the destination shared by a logger and the loggers derived from it.
+type output struct {
+	sync.Mutex
+	w io.Writer
+}
+
+// Logger writes the lines at or above its level as JSON objects, with the time, level and message
+// followed by the fields of the logger and those of the line. Fields are given as alternating keys
+// and values; URLs (*url.URL) are logged with their credentials redacted (see RedactUrl).
+type Logger struct {
+	out    *output
+	level  Level
+	fields []interface{}
+}
+
+func New(w io.Writer, level Level) *Logger {
+	return &Logger{out: &output{w: w}, level: level}
+}
+
+// Discard returns a logger that writes nothing.
+func Discard() *Logger {
+	return New(ioutil.Discard, LevelError+1)
+}
+
+// With returns a logger adding the given fields to every line.
+func (l *Logger) With(keyvals ...interface{}) *Logger {
+	fields := make([]interface{}, 0, len(l.fields)+len(keyvals))
+	fields = append(fields, l.fields...)
+	fields = append(fields, keyvals...)
+	return &Logger{out: l.out, level: l.level, fields: fields}
+}
+
+// Ctx returns a logger adding the ID of the request `ctx` belongs to (if any) to every line.
+func (l *Logger) Ctx(ctx context.Context) *Logger {
+	if id := RequestId(ctx); id != "" {
+		return l.With("requestId", id)
+	}
+	return l
+}
+
+func (l *Logger) Debug(msg string, keyvals ...interface{}) {
+	l.log(LevelDebug, msg, keyvals)
+}
+
+func (l *Logger) Info(msg string, keyvals ...interface{}) {
+	l.log(LevelInfo, msg, keyvals)
+}
+
+func (l *Logger) Warn(msg string, keyvals ...interface{}) {
+	l.log(LevelWarn, msg, keyvals)
+}
+
+func (l *Logger) Error(msg string, keyvals ...interface{}) {
+	l.log(LevelError, msg, keyvals)
+}
+
+func (l *Logger) log(level Level, msg string, keyvals []interface{}) {
+	if level < l.level {
+		return
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(`{"time":`)
+	writeJson(&buf, time.Now().UTC().Format(time.RFC3339Nano))
+	buf.WriteString(`,"level":`)
+	writeJson(&buf, level.String())
+	buf.WriteString(`,"msg":`)
+	writeJson(&buf, msg)
+	writeFields(&buf, l.fields)
+	writeFields(&buf, keyvals)
+	buf.WriteString("}\n")
+
+	l.out.Lock()
+	defer l.out.Unlock()
+	l.out.w.Write(buf.Bytes())
+}
+
+func writeFields(buf *bytes.Buffer, keyvals []interface{}) {
+	for i := 0; i < len(keyvals); i += 2 {
+		var value interface{}
+		if i+1 < len(keyvals) {
+			value = keyvals[i+1]
+		}
+		buf.WriteByte(',')
+		writeJson(buf, fmt.Sprint(keyvals[i]))
+		buf.WriteByte(':')
+		writeJson(buf, fieldValue(value))
+	}
+}
+
+// fieldValue converts the values that would not be logged usefully as JSON (e.g. errors).
+func fieldValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case *url.URL:
+		return RedactUrl(v.String())
+	case error:
+		return v.Error()
+	case time.Duration:
+		return v.String()
+	case fmt.Stringer:
+		return v.String()
+	default:
+		return v
+	}
+}
+
+func writeJson(buf *bytes.Buffer, v interface{}) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		b, _ = json.Marshal(fmt.Sprint(v))
+	}
+	buf.Write(b)
+}
+
+// redactedParams are the query parameters holding credentials, e.g. those authenticating
+// the calls to Marvel's API.
+var redactedParams = []string{"apikey", "hash", "token", "secret"}
+
+// RedactUrl returns the URL with the values of the query parameters holding credentials,
+// and any password, replaced by a placeholder. An unparseable URL is redacted entirely.
+func RedactUrl(rawUrl string) string {
+	u, err := url.Parse(rawUrl)
+	if err != nil {
+		return "REDACTED"
+	}
+
+	q := u.Query()
+	redacted := false
+	for _, param := range redactedParams {
+		if _, exists := q[param]; exists {
+			q.Set(param, "REDACTED")
+			redacted = true
+		}
+	}
+	if redacted {
+		u.RawQuery = q.Encode()
+	}
+
+	return u.Redacted()
+}
+
+type contextKey int
+
+const requestIdKey contextKey = 0
+
+// WithRequestId returns a copy of `ctx` belonging to the request with the given ID.
+func WithRequestId(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIdKey, id)
+}
+
+// RequestId returns the ID of the request `ctx` belongs to, or an empty string if none.
+func RequestId(ctx context.Context) string {
+	id, _ := ctx.Value(requestIdKey).(string)
+	return id
+}
@@ -0,0 +1,127 @@
+package marvel
+
+import (
+	"sync"
+	"time"
+)
+
+// CallBudgetConfig configures the tracking of calls made against
+// Marvel's daily API call limit.
+type CallBudgetConfig struct {
+	// Limit is the number of calls allowed per period. Zero disables enforcement.
+	Limit int `envconfig:"LIMIT" default:"3000"`
+	// Reserve is the number of calls kept untouched; once the remaining budget reaches
+	// it, calls to Marvel's API are refused and only cached data is served.
+	Reserve int `envconfig:"RESERVE" default:"100"`
+	// ResetTime is the time of day (HH:MM, in UTC) when the budget is replenished.
+	ResetTime string `envconfig:"RESET_TIME" default:"00:00"`
+	// ResetInterval is the period between budget resets.
+	ResetInterval time.Duration `envconfig:"RESET_INTERVAL" default:"24h"`
+}
+
+func (c *CallBudgetConfig) resetInterval() time.Duration {
+	if c.ResetInterval <= 0 {
+		return 24 * time.Hour
+	}
+	return c.ResetInterval
+}
+
+// BudgetStatus is a snapshot of the call budget.
+type BudgetStatus struct {
+	Limit     int       `json:"limit"`
+	Used      int       `json:"used"`
+	Remaining int       `json:"remaining"`
+	Reserve   int       `json:"reserve"`
+	ResetsAt  time.Time `json:"resetsAt"`
+}
+
+// BudgetReporter is the interface for reporting the state of the call budget.
+type BudgetReporter interface {
+	Status() BudgetStatus
+}
+
+// CallBudget counts calls made to Marvel's API and refuses new ones
+// once only the configured reserve is left.
+type CallBudget struct {
+	sync.Mutex
+	cfg      CallBudgetConfig
+	used     int
+	resetsAt time.Time
+	now      func() time.Time
+}
+
+func NewCallBudget(cfg CallBudgetConfig) *CallBudget {
+	b := &CallBudget{
+		cfg: cfg,
+		now: time.Now,
+	}
+	b.resetsAt = b.nextReset(b.now())
+	return b
+}
+
+// Take records a call to Marvel's API, returning false (and recording nothing)
+// if the call would eat into the reserve.
+func (b *CallBudget) Take() bool {
+	b.Lock()
+	defer b.Unlock()
+
+	b.resetIfDue()
+
+	if b.cfg.Limit > 0 && b.cfg.Limit-b.used <= b.cfg.Reserve {
+		return false
+	}
+
+	b.used++
+	return true
+}
+
+// Status returns a snapshot of the call budget.
+func (b *CallBudget) Status() BudgetStatus {
+	b.Lock()
+	defer b.Unlock()
+
+	b.resetIfDue()
+
+	remaining := b.cfg.Limit - b.used
+	if remaining < 0 {
+		remaining = 0
+	}
+
+	return BudgetStatus{
+		Limit:     b.cfg.Limit,
+		Used:      b.used,
+		Remaining: remaining,
+		Reserve:   b.cfg.Reserve,
+		ResetsAt:  b.resetsAt,
+	}
+}
+
+func (b *CallBudget) resetIfDue() {
+	now := b.now()
+	if now.Before(b.resetsAt) {
+		return
+	}
+	b.used = 0
+	b.resetsAt = b.nextReset(now)
+}
+
+// nextReset computes the first reset time (following the configured
+// schedule) that comes after `now`.
+func (b *CallBudget) nextReset(now time.Time) time.Time {
+	now = now.UTC()
+	interval := b.cfg.resetInterval()
+
+	var hour, min int
+	if t, err := time.Parse("15:04", b.cfg.ResetTime); err == nil {
+		hour, min = t.Hour(), t.Minute()
+	}
+
+	next := time.Date(now.Year(), now.Month(), now.Day(), hour, min, 0, 0, time.UTC)
+	for next.After(now) {
+		next = next.Add(-interval)
+	}
+	for !next.After(now) {
+		next = next.Add(interval)
+	}
+	return next
+}
@@ -0,0 +1,297 @@
+package marvel
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gkatanacio/marvel-characters-api/internal/errs"
+)
+
+// ChangeType is the kind of change made to a character.
+type ChangeType string
+
+const (
+	ChangeAdded    ChangeType = "added"
+	ChangeModified ChangeType = "modified"
+	ChangeRemoved  ChangeType = "removed"
+)
+
+// Change is a change to a character, as found by a sync of the cache with Marvel's API.
+type Change struct {
+	seq  int64
+	Id   int        `json:"id"`
+	Type ChangeType `json:"type"`
+	// Time is when the change was found by a sync
+	Time time.Time `json:"time"`
+	// Modified is when the character was last modified according to Marvel's API (unset for removals)
+	Modified *time.Time `json:"modified,omitempty"`
+}
+
+// ChangeQuery selects the changes to return: those found after `Since`, or after the position
+// given by `Cursor` (as returned by a previous query) if set.
+type ChangeQuery struct {
+	Since  time.Time
+	Cursor string
+	Limit  int
+}
+
+// ChangePage is a page of changes, along with the cursor to resume from to get the following ones.
+type ChangePage struct {
+	Changes []*Change `json:"changes"`
+	Cursor  string    `json:"cursor"`
+	HasMore bool      `json:"hasMore"`
+}
+
+// ChangeLog keeps the most recent changes found by the syncs of the cache, in the order they were
+// found. The first sync recorded is the baseline: its characters are not recorded as changes, but
+// every change after it is, until the log is full and the oldest changes are evicted.
+type ChangeLog struct {
+	sync.RWMutex
+	// maxSize is the maximum number of changes kept (zero means no limit)
+	maxSize int
+	changes []*Change
+	// epoch identifies the baseline of the log, so cursors from a different log are rejected
+	epoch int64
+	// horizon is the time since which the log holds every change (zero until the baseline is recorded)
+	horizon time.Time
+	// lastSeq is the sequence number of the most recent change
+	lastSeq int64
+	// evictedSeq is the sequence number of the most recent change evicted from the log
+	evictedSeq int64
+	// subscriptions are sent the changes as they are recorded
+	subscriptions map[*ChangeSubscription]bool
+}
+
+func NewChangeLog(maxSize int) *ChangeLog {
+	return &ChangeLog{
+		maxSize:       maxSize,
+		subscriptions: make(map[*ChangeSubscription]bool),
+	}
+}
+
+// ChangeEvent is a change along with its ID in the stream of changes, which is also the cursor
+// to resume from to get the changes that follow it.
+type ChangeEvent struct {
+	Id     string
+	Change *Change
+}
+
+// ChangeSubscription receives the changes recorded in a change log.
+type ChangeSubscription struct {
+	// Backlog holds the changes that were already recorded after the position subscribed from
+	Backlog []*ChangeEvent
+	// Events receives the changes as they are recorded. It is closed when the subscription is
+	// closed, or when the subscriber falls too far behind (so it never blocks recording changes).
+	Events <-chan *ChangeEvent
+	events chan *ChangeEvent
+	log    *ChangeLog
+}
+
+// Close stops sending changes to the subscription.
+func (s *ChangeSubscription) Close() {
+	s.log.Lock()
+	defer s.log.Unlock()
+	s.log.unsubscribe(s)
+}
+
+// Record appends the changes found by a sync made at time `at`. The first call records the baseline,
+// so its changes are dropped.
+func (l *ChangeLog) Record(changes []*Change, at time.Time) {
+	l.Lock()
+	defer l.Unlock()
+
+	if l.horizon.IsZero() {
+		l.horizon = at
+		l.epoch = at.UnixNano()
+		return
+	}
+
+	for _, c := range changes {
+		l.lastSeq++
+		c.seq = l.lastSeq
+		c.Time = at
+		l.changes = append(l.changes, c)
+		l.publish(c)
+	}
+
+	if l.maxSize > 0 && len(l.changes) > l.maxSize {
+		evicted := l.changes[:len(l.changes)-l.maxSize]
+		last := evicted[len(evicted)-1]
+		l.evictedSeq = last.seq
+		if last.Time.After(l.horizon) {
+			l.horizon = last.Time
+		}
+		l.changes = append([]*Change(nil), l.changes[len(evicted):]...)
+	}
+}
+
+// Changes returns a page of the changes selected by `query`. It returns a Gone error if some of the
+// changes selected may have been evicted from the log, or were made before its baseline.
+func (l *ChangeLog) Changes(query ChangeQuery) (*ChangePage, error) {
+	l.RLock()
+	defer l.RUnlock()
+
+	if l.horizon.IsZero() {
+		return nil, errs.NewServiceUnavailable("change log not started yet")
+	}
+
+	start := 0
+	if query.Cursor != "" {
+		var err error
+		if start, err = l.cursorIndex(query.Cursor); err != nil {
+			return nil, err
+		}
+	} else {
+		if query.Since.Before(l.horizon) {
+			return nil, errs.NewGone("changes since " + query.Since.UTC().Format(time.RFC3339) +
+				" are not available, the oldest available are since " + l.horizon.UTC().Format(time.RFC3339))
+		}
+		for start < len(l.changes) && !l.changes[start].Time.After(query.Since) {
+			start++
+		}
+	}
+
+	end := len(l.changes)
+	if query.Limit > 0 && start+query.Limit < end {
+		end = start + query.Limit
+	}
+
+	page := &ChangePage{
+		Changes: make([]*Change, 0, end-start),
+		HasMore: end < len(l.changes),
+	}
+	for _, c := range l.changes[start:end] {
+		changeCopy := *c
+		page.Changes = append(page.Changes, &changeCopy)
+	}
+
+	cursorSeq := l.lastSeq
+	if page.HasMore {
+		cursorSeq = l.changes[end-1].seq
+	}
+	page.Cursor = l.cursor(cursorSeq)
+
+	return page, nil
+}
+
+// Subscribe starts sending the changes recorded from now on to a new subscription, which is
+// dropped if more than `bufferSize` changes are waiting to be received. If `cursor` is given, the
+// changes recorded after it are put in the backlog of the subscription.
+func (l *ChangeLog) Subscribe(cursor string, bufferSize int) (*ChangeSubscription, error) {
+	l.Lock()
+	defer l.Unlock()
+
+	var backlog []*ChangeEvent
+	if cursor != "" {
+		if l.horizon.IsZero() {
+			return nil, errs.NewServiceUnavailable("change log not started yet")
+		}
+		start, err := l.cursorIndex(cursor)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range l.changes[start:] {
+			backlog = append(backlog, l.newEvent(c))
+		}
+	}
+
+	if bufferSize < 1 {
+		bufferSize = 1
+	}
+	events := make(chan *ChangeEvent, bufferSize)
+	sub := &ChangeSubscription{Backlog: backlog, Events: events, events: events, log: l}
+	l.subscriptions[sub] = true
+
+	return sub, nil
+}
+
+// CloseSubscriptions closes every subscription to the log.
+func (l *ChangeLog) CloseSubscriptions() {
+	l.Lock()
+	defer l.Unlock()
+
+	for sub := range l.subscriptions {
+		l.unsubscribe(sub)
+	}
+}
+
+// publish sends the change to every subscription, dropping those that have fallen behind.
+func (l *ChangeLog) publish(c *Change) {
+	for sub := range l.subscriptions {
+		select {
+		case sub.events <- l.newEvent(c):
+		default:
+			l.unsubscribe(sub)
+		}
+	}
+}
+
+func (l *ChangeLog) unsubscribe(sub *ChangeSubscription) {
+	if l.subscriptions[sub] {
+		delete(l.subscriptions, sub)
+		close(sub.events)
+	}
+}
+
+func (l *ChangeLog) newEvent(c *Change) *ChangeEvent {
+	changeCopy := *c
+	return &ChangeEvent{Id: l.cursor(c.seq), Change: &changeCopy}
+}
+
+// cursorIndex returns the index in the log of the first change after `cursor`.
+func (l *ChangeLog) cursorIndex(cursor string) (int, error) {
+	var epoch, seq int64
+	if _, err := fmt.Sscanf(cursor, "%d.%d", &epoch, &seq); err != nil {
+		return 0, errs.NewBadRequest("invalid cursor")
+	}
+	if epoch != l.epoch {
+		return 0, errs.NewGone("changes after the cursor are no longer available")
+	}
+	if seq < 0 || seq > l.lastSeq {
+		return 0, errs.NewBadRequest("invalid cursor")
+	}
+	if seq < l.evictedSeq {
+		return 0, errs.NewGone("changes after the cursor are no longer available")
+	}
+	return int(seq - l.evictedSeq), nil
+}
+
+func (l *ChangeLog) cursor(seq int64) string {
+	return fmt.Sprintf("%d.%d", l.epoch, seq)
+}
+
+// snapshot returns the contents of the log, for persisting it.
+func (l *ChangeLog) snapshot() *changeLogSnapshot {
+	l.RLock()
+	defer l.RUnlock()
+
+	changes := make([]*changeSnapshot, 0, len(l.changes))
+	for _, c := range l.changes {
+		changes = append(changes, &changeSnapshot{Seq: c.seq, Change: *c})
+	}
+	return &changeLogSnapshot{
+		Epoch:      l.epoch,
+		Horizon:    l.horizon,
+		LastSeq:    l.lastSeq,
+		EvictedSeq: l.evictedSeq,
+		Changes:    changes,
+	}
+}
+
+// restore replaces the contents of the log with those of the snapshot.
+func (l *ChangeLog) restore(snapshot *changeLogSnapshot) {
+	l.Lock()
+	defer l.Unlock()
+
+	l.epoch = snapshot.Epoch
+	l.horizon = snapshot.Horizon
+	l.lastSeq = snapshot.LastSeq
+	l.evictedSeq = snapshot.EvictedSeq
+	l.changes = make([]*Change, 0, len(snapshot.Changes))
+	for _, cs := range snapshot.Changes {
+		c := cs.Change
+		c.seq = cs.Seq
+		l.changes = append(l.changes, &c)
+	}
+}
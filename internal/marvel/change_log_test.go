@@ -0,0 +1,151 @@
+package marvel_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gkatanacio/marvel-characters-api/internal/errs"
+	"github.com/gkatanacio/marvel-characters-api/internal/marvel"
+	"github.com/stretchr/testify/assert"
+)
+
+func changeIds(page *marvel.ChangePage) []int {
+	ids := make([]int, 0, len(page.Changes))
+	for _, c := range page.Changes {
+		ids = append(ids, c.Id)
+	}
+	return ids
+}
+
+func Test_ChangeLog_Changes_Since(t *testing.T) {
+	// given
+	t0 := time.Date(2021, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	changeLog := marvel.NewChangeLog(0)
+	changeLog.Record([]*marvel.Change{{Id: 1, Type: marvel.ChangeAdded}}, t0)
+	changeLog.Record([]*marvel.Change{{Id: 2, Type: marvel.ChangeAdded}}, t0.Add(time.Minute))
+	changeLog.Record([]*marvel.Change{{Id: 3, Type: marvel.ChangeModified}, {Id: 4, Type: marvel.ChangeRemoved}}, t0.Add(2*time.Minute))
+
+	// when
+	page, err := changeLog.Changes(marvel.ChangeQuery{Since: t0.Add(time.Minute)})
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, []int{3, 4}, changeIds(page))
+	assert.Equal(t, marvel.ChangeModified, page.Changes[0].Type)
+	assert.Equal(t, t0.Add(2*time.Minute), page.Changes[0].Time)
+	assert.False(t, page.HasMore)
+}
+
+func Test_ChangeLog_Changes_Cursor(t *testing.T) {
+	// given
+	t0 := time.Now()
+
+	changeLog := marvel.NewChangeLog(0)
+	changeLog.Record(nil, t0)
+	changeLog.Record([]*marvel.Change{{Id: 1}, {Id: 2}, {Id: 3}}, t0.Add(time.Minute))
+
+	first, err := changeLog.Changes(marvel.ChangeQuery{Since: t0, Limit: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 2}, changeIds(first))
+	assert.True(t, first.HasMore)
+
+	// when
+	second, err := changeLog.Changes(marvel.ChangeQuery{Cursor: first.Cursor, Limit: 2})
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, []int{3}, changeIds(second))
+	assert.False(t, second.HasMore)
+
+	changeLog.Record([]*marvel.Change{{Id: 4}}, t0.Add(2*time.Minute))
+	third, err := changeLog.Changes(marvel.ChangeQuery{Cursor: second.Cursor})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{4}, changeIds(third))
+}
+
+func Test_ChangeLog_Changes_Unavailable(t *testing.T) {
+	// given
+	t0 := time.Now()
+
+	changeLog := marvel.NewChangeLog(2)
+	changeLog.Record(nil, t0)
+	baselinePage, err := changeLog.Changes(marvel.ChangeQuery{Since: t0})
+	assert.NoError(t, err)
+	changeLog.Record([]*marvel.Change{{Id: 1}}, t0.Add(time.Minute))
+	changeLog.Record([]*marvel.Change{{Id: 2}, {Id: 3}}, t0.Add(2*time.Minute))
+
+	tests := map[string]struct {
+		query       marvel.ChangeQuery
+		expectedErr error
+	}{
+		"before the baseline":     {query: marvel.ChangeQuery{Since: t0.Add(-time.Minute)}, expectedErr: &errs.Gone{}},
+		"changes evicted":         {query: marvel.ChangeQuery{Since: t0}, expectedErr: &errs.Gone{}},
+		"cursor before evicted":   {query: marvel.ChangeQuery{Cursor: baselinePage.Cursor}, expectedErr: &errs.Gone{}},
+		"malformed cursor":        {query: marvel.ChangeQuery{Cursor: "abc"}, expectedErr: &errs.BadRequest{}},
+		"cursor from another log": {query: marvel.ChangeQuery{Cursor: "1.1"}, expectedErr: &errs.Gone{}},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			// when
+			_, err := changeLog.Changes(tc.query)
+
+			// then
+			assert.IsType(t, tc.expectedErr, err)
+		})
+	}
+
+	page, err := changeLog.Changes(marvel.ChangeQuery{Since: t0.Add(time.Minute)})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{2, 3}, changeIds(page))
+}
+
+func Test_ChangeLog_Subscribe_BacklogAndLive(t *testing.T) {
+	// given
+	t0 := time.Now()
+
+	changeLog := marvel.NewChangeLog(0)
+	changeLog.Record(nil, t0)
+	page, err := changeLog.Changes(marvel.ChangeQuery{Since: t0})
+	assert.NoError(t, err)
+	changeLog.Record([]*marvel.Change{{Id: 1, Type: marvel.ChangeAdded}}, t0.Add(time.Minute))
+
+	// when
+	sub, err := changeLog.Subscribe(page.Cursor, 10)
+	changeLog.Record([]*marvel.Change{{Id: 2, Type: marvel.ChangeRemoved}}, t0.Add(2*time.Minute))
+
+	// then
+	assert.NoError(t, err)
+	assert.Len(t, sub.Backlog, 1)
+	assert.Equal(t, 1, sub.Backlog[0].Change.Id)
+	live := <-sub.Events
+	assert.Equal(t, 2, live.Change.Id)
+	resumed, err := changeLog.Changes(marvel.ChangeQuery{Cursor: sub.Backlog[0].Id})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{2}, changeIds(resumed))
+
+	sub.Close()
+	_, open := <-sub.Events
+	assert.False(t, open)
+}
+
+func Test_ChangeLog_Subscribe_SlowSubscriberDropped(t *testing.T) {
+	// given
+	changeLog := marvel.NewChangeLog(0)
+	changeLog.Record(nil, time.Now())
+
+	sub, err := changeLog.Subscribe("", 2)
+	assert.NoError(t, err)
+
+	// when
+	changeLog.Record([]*marvel.Change{{Id: 1}, {Id: 2}, {Id: 3}}, time.Now())
+
+	// then
+	var received []int
+	for e := range sub.Events {
+		received = append(received, e.Change.Id)
+	}
+	assert.Equal(t, []int{1, 2}, received)
+	sub.Close()
+}
@@ -1,97 +1,193 @@
 package marvel
 
 import (
+	"context"
 	"crypto/md5"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
-	"log"
 	"net/http"
+	"net/url"
 	"strconv"
 	"time"
 
 	"github.com/gkatanacio/marvel-characters-api/internal/errs"
+	"github.com/gkatanacio/marvel-characters-api/internal/logging"
 	"golang.org/x/sync/errgroup"
 )
 
 // MarvelDataFetcher is the interface to abstract the actual
 // calls to Marvel's API.
 type MarvelDataFetcher interface {
-	GetAllCharacters(modifiedSince *time.Time) ([]*MarvelApiCharacterData, error)
-	GetCharacter(id int) (*MarvelApiCharacterData, error)
+	GetAllCharacters(ctx context.Context, modifiedSince *time.Time) ([]*MarvelApiCharacterData, error)
+	GetComicCharactersPage(ctx context.Context, modifiedSince *time.Time, offset int) ([]*ComicCharacters, int, error)
+	GetCharacter(ctx context.Context, id int) (*MarvelApiCharacterData, error)
+	SearchCharacters(ctx context.Context, search CharacterSearch) ([]*MarvelApiCharacterData, int, error)
+	GetComics(ctx context.Context, query ResourceQuery) ([]*MarvelApiComicData, int, error)
+	GetComic(ctx context.Context, id int) (*MarvelApiComicData, error)
+	GetSeriesList(ctx context.Context, query ResourceQuery) ([]*MarvelApiSeriesData, int, error)
+	GetSeries(ctx context.Context, id int) (*MarvelApiSeriesData, error)
+	GetEvents(ctx context.Context, query ResourceQuery) ([]*MarvelApiEventData, int, error)
+	GetEvent(ctx context.Context, id int) (*MarvelApiEventData, error)
+	GetStories(ctx context.Context, query ResourceQuery) ([]*MarvelApiStoryData, int, error)
+	GetStory(ctx context.Context, id int) (*MarvelApiStoryData, error)
+	GetCreators(ctx context.Context, query ResourceQuery) ([]*MarvelApiCreatorData, int, error)
+	GetCreator(ctx context.Context, id int) (*MarvelApiCreatorData, error)
+	GetCharacterComics(ctx context.Context, id int, query ResourceQuery) ([]*MarvelApiComicData, int, error)
+	GetCharacterSeries(ctx context.Context, id int, query ResourceQuery) ([]*MarvelApiSeriesData, int, error)
+	GetCharacterEvents(ctx context.Context, id int, query ResourceQuery) ([]*MarvelApiEventData, int, error)
+	GetCharacterStories(ctx context.Context, id int, query ResourceQuery) ([]*MarvelApiStoryData, int, error)
 }
 
 // Client is the concrete implementation of MarvelDataFetcher.
 type Client struct {
 	cfg        *Config
 	httpClient *http.Client
+	rnd        *lockedRand
+	// slots is a semaphore limiting concurrent calls to Marvel's API (nil if unlimited)
+	slots  chan struct{}
+	budget *CallBudget
+	etags  *etagStore
+	logger *logging.Logger
 }
 
-func NewClient(cfg *Config) *Client {
+func NewClient(cfg *Config, logger *logging.Logger) *Client {
+	var slots chan struct{}
+	if cfg.MaxConcurrentRequests > 0 {
+		slots = make(chan struct{}, cfg.MaxConcurrentRequests)
+	}
+
 	return &Client{
 		cfg: cfg,
 		httpClient: &http.Client{
 			Timeout: time.Second * 15,
 		},
+		rnd:    newLockedRand(),
+		slots:  slots,
+		budget: NewCallBudget(cfg.Budget),
+		etags:  newEtagStore(),
+		logger: logger,
 	}
 }
 
+// Budget returns the tracker of calls made against Marvel's daily API call limit.
+func (c *Client) Budget() *CallBudget {
+	return c.budget
+}
+
 // GetAllCharacters fetches all characters modified since the optionally
 // provided `modifiedSince` timestamp. If `modifiedSince` is nil, GetAllCharacters fetches all
 // the Marvel characters. The MarvelApiCharacterData with the most recent
 // MarvelApiCharacterData.Modified is set as the first element in the returned slice.
-func (c *Client) GetAllCharacters(modifiedSince *time.Time) ([]*MarvelApiCharacterData, error) {
+// The remaining pages are cancelled as soon as fetching one of them fails.
+func (c *Client) GetAllCharacters(ctx context.Context, modifiedSince *time.Time) ([]*MarvelApiCharacterData, error) {
+	qp := map[string]string{
+		"orderBy": "-modified",
+	}
+	if modifiedSince != nil {
+		qp["modifiedSince"] = modifiedSince.Format(dateFormatMarvelApi)
+	}
+
 	var characters []*MarvelApiCharacterData
+	err := c.getAllPages(ctx, "/v1/public/characters", qp, func(marvelApiResp *MarvelApiResponse) error {
+		pageCharacters, err := decodeCharacters(marvelApiResp)
+		if err != nil {
+			return err
+		}
+		characters = append(characters, pageCharacters...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
 
-	batchSize := 100
+	return characters, nil
+}
 
+// GetComicCharactersPage fetches a page of the characters appearing in each comic (excluding
+// variant covers) modified since the optionally provided `modifiedSince` timestamp, starting at `offset`.
+// Comics are ordered from the most recently modified, so that a crawl of every page can be resumed
+// from the offset it stopped at. The total number of such comics is returned along with the page.
+// Only the characters are kept from each comic, as keeping every comic in full would take up a lot of memory.
+func (c *Client) GetComicCharactersPage(ctx context.Context, modifiedSince *time.Time, offset int) ([]*ComicCharacters, int, error) {
 	qp := map[string]string{
-		"limit":   strconv.Itoa(batchSize),
-		"orderBy": "-modified",
+		"orderBy":    "-modified",
+		"noVariants": "true",
+		"limit":      strconv.Itoa(c.cfg.pageSize()),
+		"offset":     strconv.Itoa(offset),
 	}
 	if modifiedSince != nil {
 		qp["modifiedSince"] = modifiedSince.Format(dateFormatMarvelApi)
 	}
 
-	marvelApiResp, err := c.httpGet("/v1/public/characters", qp)
+	marvelApiResp, err := c.crawlGet(ctx, "/v1/public/comics", qp)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
-	for _, r := range marvelApiResp.Data.Results {
-		char, err := resultToMarvelApiCharacterData(r)
-		if err != nil {
-			return nil, err
-		}
-		characters = append(characters, char)
+	comics := []*MarvelApiComicData{}
+	if err := decodeResults(marvelApiResp, &comics); err != nil {
+		return nil, 0, err
+	}
+
+	comicChars := make([]*ComicCharacters, 0, len(comics))
+	for _, comic := range comics {
+		comicChars = append(comicChars, comic.toComicCharacters())
+	}
+
+	return comicChars, marvelApiResp.Data.Total, nil
+}
+
+// getAllPages fetches every page of the resources at `path`, passing each response to `handle`
+// (one at a time). The first page is fetched first to learn the total number of resources, then
+// the remaining pages are fetched concurrently, and handled in no particular order. The remaining
+// pages are cancelled as soon as fetching or handling one of them fails.
+func (c *Client) getAllPages(ctx context.Context, path string, qp map[string]string, handle func(*MarvelApiResponse) error) error {
+	batchSize := c.cfg.pageSize()
+
+	firstQp := make(map[string]string)
+	for k, v := range qp {
+		firstQp[k] = v
+	}
+	firstQp["limit"] = strconv.Itoa(batchSize)
+
+	marvelApiResp, err := c.httpGet(ctx, path, firstQp)
+	if err != nil {
+		return err
 	}
 
-	// fetch remaining characters (asynchronously) if needed
+	if err := handle(marvelApiResp); err != nil {
+		return err
+	}
+
+	// fetch remaining pages (asynchronously) if needed
 	if marvelApiResp.Data.Total > batchSize {
-		eg := new(errgroup.Group)
-		remainingChars := make(chan *MarvelApiCharacterData)
+		// cancelled on return, to stop fetching the remaining pages if handling one of them fails
+		fetchCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		eg, egCtx := errgroup.WithContext(fetchCtx)
+		remainingResps := make(chan *MarvelApiResponse)
 
 		for i := batchSize; i < marvelApiResp.Data.Total; i += batchSize {
 			offset := i
 			eg.Go(func() error {
 				qpCopy := make(map[string]string)
-				for k, v := range qp {
+				for k, v := range firstQp {
 					qpCopy[k] = v
 				}
 				qpCopy["offset"] = strconv.Itoa(offset)
 
-				remainingResp, err := c.httpGet("/v1/public/characters", qpCopy)
+				remainingResp, err := c.httpGet(egCtx, path, qpCopy)
 				if err != nil {
 					return err
 				}
 
-				for _, r := range remainingResp.Data.Results {
-					char, err := resultToMarvelApiCharacterData(r)
-					if err != nil {
-						return err
-					}
-					remainingChars <- char
+				select {
+				case remainingResps <- remainingResp:
+				case <-egCtx.Done():
+					return egCtx.Err()
 				}
 
 				return nil
@@ -100,38 +196,260 @@ func (c *Client) GetAllCharacters(modifiedSince *time.Time) ([]*MarvelApiCharact
 
 		go func() {
 			eg.Wait()
-			close(remainingChars)
+			close(remainingResps)
 		}()
 
-		for rc := range remainingChars {
-			characters = append(characters, rc)
+		for rr := range remainingResps {
+			if err := handle(rr); err != nil {
+				return err
+			}
 		}
 
 		if err := eg.Wait(); err != nil {
-			return nil, err
+			return err
 		}
 	}
 
-	return characters, nil
+	return nil
 }
 
-// GetCharacter fetches the character's data, given a character ID.
-func (c *Client) GetCharacter(id int) (*MarvelApiCharacterData, error) {
+// getPage fetches a single page of the resources at `path`, unmarshalling them into `v`
+// (see decodeResults). The total number of resources is returned.
+func (c *Client) getPage(ctx context.Context, path string, query ResourceQuery, v interface{}) (int, error) {
+	qp := map[string]string{
+		"limit":  strconv.Itoa(query.Limit),
+		"offset": strconv.Itoa(query.Offset),
+	}
+	if query.OrderBy != "" {
+		qp["orderBy"] = query.OrderBy
+	}
+	if query.Format != "" {
+		qp["format"] = query.Format
+	}
+	if query.DateRange != "" {
+		qp["dateRange"] = query.DateRange
+	}
+
+	marvelApiResp, err := c.httpGet(ctx, path, qp)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := decodeResults(marvelApiResp, v); err != nil {
+		return 0, err
+	}
+
+	return marvelApiResp.Data.Total, nil
+}
+
+// getById fetches the resource at `path` with the given ID, unmarshalling it into `v`
+// (see decodeResults). An ID not known to Marvel's API results in a 404 error from httpGet().
+func (c *Client) getById(ctx context.Context, path string, id int, v interface{}) error {
 	qp := map[string]string{
 		"limit": "1",
 	}
 
-	marvelApiResp, err := c.httpGet(fmt.Sprintf("/v1/public/characters/%d", id), qp)
+	marvelApiResp, err := c.httpGet(ctx, fmt.Sprintf("%s/%d", path, id), qp)
+	if err != nil {
+		return err
+	}
+
+	return decodeResults(marvelApiResp, v)
+}
+
+// GetCharacter fetches the character's data, given a character ID.
+func (c *Client) GetCharacter(ctx context.Context, id int) (*MarvelApiCharacterData, error) {
+	characters := []*MarvelApiCharacterData{}
+	if err := c.getById(ctx, "/v1/public/characters", id, &characters); err != nil {
+		return nil, err
+	}
+
+	// safe to assume characters[0] has value because no Results will throw 404 error in httpGet()
+	return characters[0], nil
+}
+
+// SearchCharacters fetches a page of the characters matching the name criteria in `search`,
+// sorted by name. The total number of matching characters is returned along with the page.
+func (c *Client) SearchCharacters(ctx context.Context, search CharacterSearch) ([]*MarvelApiCharacterData, int, error) {
+	qp := map[string]string{
+		"limit":   strconv.Itoa(search.Limit),
+		"offset":  strconv.Itoa(search.Offset),
+		"orderBy": "name",
+	}
+	if search.Name != "" {
+		qp["name"] = search.Name
+	}
+	if search.NameStartsWith != "" {
+		qp["nameStartsWith"] = search.NameStartsWith
+	}
+
+	marvelApiResp, err := c.httpGet(ctx, "/v1/public/characters", qp)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	characters, err := decodeCharacters(marvelApiResp)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return characters, marvelApiResp.Data.Total, nil
+}
+
+// GetComics fetches a page of comics. The total number of comics is returned along with the page.
+func (c *Client) GetComics(ctx context.Context, query ResourceQuery) ([]*MarvelApiComicData, int, error) {
+	comics := []*MarvelApiComicData{}
+	total, err := c.getPage(ctx, "/v1/public/comics", query, &comics)
+	if err != nil {
+		return nil, 0, err
+	}
+	return comics, total, nil
+}
+
+// GetComic fetches the comic's data, given a comic ID.
+func (c *Client) GetComic(ctx context.Context, id int) (*MarvelApiComicData, error) {
+	comics := []*MarvelApiComicData{}
+	if err := c.getById(ctx, "/v1/public/comics", id, &comics); err != nil {
+		return nil, err
+	}
+	return comics[0], nil
+}
+
+// GetSeriesList fetches a page of series. The total number of series is returned along with the page.
+func (c *Client) GetSeriesList(ctx context.Context, query ResourceQuery) ([]*MarvelApiSeriesData, int, error) {
+	series := []*MarvelApiSeriesData{}
+	total, err := c.getPage(ctx, "/v1/public/series", query, &series)
+	if err != nil {
+		return nil, 0, err
+	}
+	return series, total, nil
+}
+
+// GetSeries fetches the series' data, given a series ID.
+func (c *Client) GetSeries(ctx context.Context, id int) (*MarvelApiSeriesData, error) {
+	series := []*MarvelApiSeriesData{}
+	if err := c.getById(ctx, "/v1/public/series", id, &series); err != nil {
+		return nil, err
+	}
+	return series[0], nil
+}
+
+// GetEvents fetches a page of events. The total number of events is returned along with the page.
+func (c *Client) GetEvents(ctx context.Context, query ResourceQuery) ([]*MarvelApiEventData, int, error) {
+	events := []*MarvelApiEventData{}
+	total, err := c.getPage(ctx, "/v1/public/events", query, &events)
 	if err != nil {
+		return nil, 0, err
+	}
+	return events, total, nil
+}
+
+// GetEvent fetches the event's data, given an event ID.
+func (c *Client) GetEvent(ctx context.Context, id int) (*MarvelApiEventData, error) {
+	events := []*MarvelApiEventData{}
+	if err := c.getById(ctx, "/v1/public/events", id, &events); err != nil {
 		return nil, err
 	}
+	return events[0], nil
+}
+
+// GetStories fetches a page of stories. The total number of stories is returned along with the page.
+func (c *Client) GetStories(ctx context.Context, query ResourceQuery) ([]*MarvelApiStoryData, int, error) {
+	stories := []*MarvelApiStoryData{}
+	total, err := c.getPage(ctx, "/v1/public/stories", query, &stories)
+	if err != nil {
+		return nil, 0, err
+	}
+	return stories, total, nil
+}
 
-	// safe to assume Results[0] has value because no Results will throw 404 error in httpGet()
-	return resultToMarvelApiCharacterData(marvelApiResp.Data.Results[0])
+// GetStory fetches the story's data, given a story ID.
+func (c *Client) GetStory(ctx context.Context, id int) (*MarvelApiStoryData, error) {
+	stories := []*MarvelApiStoryData{}
+	if err := c.getById(ctx, "/v1/public/stories", id, &stories); err != nil {
+		return nil, err
+	}
+	return stories[0], nil
 }
 
-func (c *Client) httpGet(path string, additionalQueryParams map[string]string) (*MarvelApiResponse, error) {
-	req, err := http.NewRequest(http.MethodGet, c.cfg.ApiBaseUrl+path, nil)
+// GetCreators fetches a page of creators. The total number of creators is returned along with the page.
+func (c *Client) GetCreators(ctx context.Context, query ResourceQuery) ([]*MarvelApiCreatorData, int, error) {
+	creators := []*MarvelApiCreatorData{}
+	total, err := c.getPage(ctx, "/v1/public/creators", query, &creators)
+	if err != nil {
+		return nil, 0, err
+	}
+	return creators, total, nil
+}
+
+// GetCreator fetches the creator's data, given a creator ID.
+func (c *Client) GetCreator(ctx context.Context, id int) (*MarvelApiCreatorData, error) {
+	creators := []*MarvelApiCreatorData{}
+	if err := c.getById(ctx, "/v1/public/creators", id, &creators); err != nil {
+		return nil, err
+	}
+	return creators[0], nil
+}
+
+// GetCharacterComics fetches a page of the comics the character with the given ID appears in.
+// The total number of such comics is returned along with the page.
+func (c *Client) GetCharacterComics(ctx context.Context, id int, query ResourceQuery) ([]*MarvelApiComicData, int, error) {
+	comics := []*MarvelApiComicData{}
+	total, err := c.getPage(ctx, fmt.Sprintf("/v1/public/characters/%d/comics", id), query, &comics)
+	if err != nil {
+		return nil, 0, err
+	}
+	return comics, total, nil
+}
+
+// GetCharacterSeries fetches a page of the series the character with the given ID appears in.
+// The total number of such series is returned along with the page.
+func (c *Client) GetCharacterSeries(ctx context.Context, id int, query ResourceQuery) ([]*MarvelApiSeriesData, int, error) {
+	series := []*MarvelApiSeriesData{}
+	total, err := c.getPage(ctx, fmt.Sprintf("/v1/public/characters/%d/series", id), query, &series)
+	if err != nil {
+		return nil, 0, err
+	}
+	return series, total, nil
+}
+
+// GetCharacterEvents fetches a page of the events the character with the given ID appears in.
+// The total number of such events is returned along with the page.
+func (c *Client) GetCharacterEvents(ctx context.Context, id int, query ResourceQuery) ([]*MarvelApiEventData, int, error) {
+	events := []*MarvelApiEventData{}
+	total, err := c.getPage(ctx, fmt.Sprintf("/v1/public/characters/%d/events", id), query, &events)
+	if err != nil {
+		return nil, 0, err
+	}
+	return events, total, nil
+}
+
+// GetCharacterStories fetches a page of the stories the character with the given ID appears in.
+// The total number of such stories is returned along with the page.
+func (c *Client) GetCharacterStories(ctx context.Context, id int, query ResourceQuery) ([]*MarvelApiStoryData, int, error) {
+	stories := []*MarvelApiStoryData{}
+	total, err := c.getPage(ctx, fmt.Sprintf("/v1/public/characters/%d/stories", id), query, &stories)
+	if err != nil {
+		return nil, 0, err
+	}
+	return stories, total, nil
+}
+
+// httpGet calls Marvel's API, making the call conditional if a response to the same request
+// is remembered, and remembering the response for the next time.
+func (c *Client) httpGet(ctx context.Context, path string, additionalQueryParams map[string]string) (*MarvelApiResponse, error) {
+	return c.get(ctx, path, additionalQueryParams, true)
+}
+
+// crawlGet calls Marvel's API for a page of the crawl through every comic. Unlike httpGet,
+// the response is not remembered for conditional calls, since the crawl would fill the etag store
+// with large responses that are hardly ever requested again.
+func (c *Client) crawlGet(ctx context.Context, path string, additionalQueryParams map[string]string) (*MarvelApiResponse, error) {
+	return c.get(ctx, path, additionalQueryParams, false)
+}
+
+func (c *Client) get(ctx context.Context, path string, additionalQueryParams map[string]string, conditional bool) (*MarvelApiResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.cfg.ApiBaseUrl+path, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -143,40 +461,145 @@ func (c *Client) httpGet(path string, additionalQueryParams map[string]string) (
 
 	addQueryParams(req, qp)
 
-	log.Println(req.URL)
+	// make the request conditional if we already have a response for it
+	var key string
+	var cached etagEntry
+	if conditional {
+		key = etagKey(path, additionalQueryParams)
+		var hasCached bool
+		if cached, hasCached = c.etags.get(key); hasCached {
+			req.Header.Set("If-None-Match", cached.etag)
+		}
+	}
+
+	logger := c.logger.Ctx(ctx).With("path", path)
+	logger.Debug("calling marvel api", "url", req.URL)
+
+	maxAttempts := c.cfg.Retry.maxAttempts()
+	for attempt := 1; ; attempt++ {
+		marvelApiResp, retryable, retryAfter, err := c.attemptGet(req, key, cached)
+		if err == nil {
+			if attempt > 1 {
+				logger.Info("marvel api call succeeded after retrying", "attempts", attempt)
+			}
+			return marvelApiResp, nil
+		}
+
+		if !retryable || attempt >= maxAttempts {
+			if attempt > 1 {
+				logger.Error("marvel api call failed after retrying", "attempts", attempt, "error", err)
+			}
+			return nil, err
+		}
+
+		delay := c.cfg.Retry.backoff(attempt, c.rnd)
+		if retryAfter > 0 {
+			if c.cfg.Retry.MaxDelay > 0 && retryAfter > c.cfg.Retry.MaxDelay {
+				logger.Warn("marvel api call failed, not retrying since Retry-After exceeds max delay",
+					"attempt", attempt, "maxAttempts", maxAttempts, "retryAfter", retryAfter, "error", err)
+				return nil, err
+			}
+			if retryAfter > delay {
+				delay = retryAfter
+			}
+		}
 
+		logger.Warn("marvel api call failed, retrying", "attempt", attempt, "maxAttempts", maxAttempts, "delay", delay, "error", err)
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// attemptGet makes a single call to Marvel's API. Aside from the result, it also reports whether
+// a failed call can be retried and how long Marvel's API asked us to wait before doing so.
+// A 304 (Not Modified) response is served from `cached`, the stored response for `key`.
+// Responses are only stored if `key` is set.
+func (c *Client) attemptGet(req *http.Request, key string, cached etagEntry) (*MarvelApiResponse, bool, time.Duration, error) {
+	if err := c.acquireSlot(req.Context()); err != nil {
+		return nil, false, 0, err
+	}
+	defer c.releaseSlot()
+
+	logger := c.logger.Ctx(req.Context()).With("path", req.URL.Path)
+
+	if !c.budget.Take() {
+		logger.Warn("marvel api call budget exhausted, refusing call")
+		return nil, false, 0, errs.NewServiceUnavailable("marvel api call budget exhausted")
+	}
+
+	start := time.Now()
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, err
+		observeApiCall(req.URL.Path, 0, time.Since(start))
+		// the URL of the request holds the credentials, and the error may end up in a response
+		if urlErr, ok := err.(*url.Error); ok {
+			urlErr.URL = logging.RedactUrl(urlErr.URL)
+		}
+		if req.Context().Err() != nil {
+			return nil, false, 0, req.Context().Err()
+		}
+		return nil, c.cfg.Retry.isRetryableErr(err), 0, err
 	}
 
 	defer resp.Body.Close()
 
 	respBody, err := ioutil.ReadAll(resp.Body)
+	observeApiCall(req.URL.Path, resp.StatusCode, time.Since(start))
 	if err != nil {
-		return nil, err
+		return nil, c.cfg.Retry.isRetryableErr(err), 0, err
 	}
 
-	if resp.StatusCode != http.StatusOK {
+	if resp.StatusCode == http.StatusNotModified && cached.body != nil {
+		logger.Debug("marvel api response not modified, using stored response")
+		respBody = cached.body
+	} else if resp.StatusCode != http.StatusOK {
+		// error responses from intermediaries (e.g. load balancers) may not be in Marvel's format
 		errResp := new(MarvelApiErrResponse)
-		if err := json.Unmarshal(respBody, &errResp); err != nil {
-			return nil, err
-		}
-		log.Printf(`error from marvel api: [%d] {status: "%s", message: "%s"}`, resp.StatusCode, errResp.Status, errResp.Message)
+		json.Unmarshal(respBody, &errResp)
+		logger.Warn("error from marvel api", "status", resp.StatusCode, "marvelStatus", errResp.Status, "message", errResp.Message)
 
 		if resp.StatusCode == http.StatusNotFound {
-			return nil, errs.NewNotFound("no results")
+			return nil, false, 0, errs.NewNotFound("no results")
 		}
 
-		return nil, errs.NewBadGateway("error response from marvel api")
+		retryAfter, _ := parseRetryAfter(resp.Header)
+		return nil, c.cfg.Retry.isRetryableStatus(resp.StatusCode), retryAfter, errs.NewBadGateway("error response from marvel api")
 	}
 
 	marvelApiResp := new(MarvelApiResponse)
 	if err := json.Unmarshal(respBody, &marvelApiResp); err != nil {
-		return nil, err
+		return nil, false, 0, err
 	}
 
-	return marvelApiResp, nil
+	if key != "" && resp.StatusCode == http.StatusOK && marvelApiResp.Etag != "" {
+		c.etags.set(key, etagEntry{etag: marvelApiResp.Etag, body: respBody})
+	}
+
+	return marvelApiResp, false, 0, nil
+}
+
+// acquireSlot blocks until the number of in-flight calls to Marvel's API
+// is below the configured limit, or until `ctx` is done.
+func (c *Client) acquireSlot(ctx context.Context) error {
+	if c.slots == nil {
+		return nil
+	}
+
+	select {
+	case c.slots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (c *Client) releaseSlot() {
+	if c.slots != nil {
+		<-c.slots
+	}
 }
 
 func (c *Client) authParams() map[string]string {
@@ -209,16 +632,19 @@ func computeHash(ts, privateKey, publicKey string) string {
 	return hex.EncodeToString(hash[:])
 }
 
-func resultToMarvelApiCharacterData(result interface{}) (*MarvelApiCharacterData, error) {
-	jsonData, err := json.Marshal(result)
-	if err != nil {
-		return nil, err
+// decodeResults unmarshals the results of a response from Marvel's API into `v`,
+// which is expected to be a pointer to a slice of the type of resource requested.
+func decodeResults(marvelApiResp *MarvelApiResponse, v interface{}) error {
+	if len(marvelApiResp.Data.Results) == 0 {
+		return nil
 	}
+	return json.Unmarshal(marvelApiResp.Data.Results, v)
+}
 
-	marvelApiCharacterData := new(MarvelApiCharacterData)
-	if err := json.Unmarshal(jsonData, marvelApiCharacterData); err != nil {
+func decodeCharacters(marvelApiResp *MarvelApiResponse) ([]*MarvelApiCharacterData, error) {
+	characters := []*MarvelApiCharacterData{}
+	if err := decodeResults(marvelApiResp, &characters); err != nil {
 		return nil, err
 	}
-
-	return marvelApiCharacterData, nil
+	return characters, nil
 }
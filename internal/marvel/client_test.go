@@ -1,12 +1,20 @@
 package marvel_test
 
 import (
+	"bytes"
+	"context"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/gkatanacio/marvel-characters-api/internal/errs"
+	"github.com/gkatanacio/marvel-characters-api/internal/logging"
 	"github.com/gkatanacio/marvel-characters-api/internal/marvel"
 	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/assert"
@@ -57,10 +65,10 @@ func Test_Client_GetAllCharacters_HappyPath(t *testing.T) {
 
 	cfg := testCfg(ts.URL)
 
-	client := marvel.NewClient(cfg)
+	client := marvel.NewClient(cfg, logging.Discard())
 
 	// when
-	chars, err := client.GetAllCharacters(nil)
+	chars, err := client.GetAllCharacters(context.Background(), nil)
 
 	// then
 	assert.NoError(t, err)
@@ -101,10 +109,10 @@ func Test_Client_GetCharacter_HappyPath(t *testing.T) {
 
 	cfg := testCfg(ts.URL)
 
-	client := marvel.NewClient(cfg)
+	client := marvel.NewClient(cfg, logging.Discard())
 
 	// when
-	charData, err := client.GetCharacter(1009351)
+	charData, err := client.GetCharacter(context.Background(), 1009351)
 
 	// then
 	assert.NoError(t, err)
@@ -117,6 +125,75 @@ func Test_Client_GetCharacter_HappyPath(t *testing.T) {
 	}, *charData)
 }
 
+func Test_Client_GetCharacter_RichFields(t *testing.T) {
+	// given
+	testResponse := `
+	{
+		"code": 200,
+		"status": "Ok",
+		"data": {
+			"offset": 0,
+			"limit": 1,
+			"total": 1,
+			"count": 1,
+			"results": [
+				{
+					"id": 1009351,
+					"name": "Hulk",
+					"description": "",
+					"modified": "2020-07-21T10:35:15-0400",
+					"thumbnail": {
+						"path": "http://i.annihil.us/u/prod/marvel/i/mg/5/a0/538615ca33ab0",
+						"extension": "jpg"
+					},
+					"resourceURI": "http://gateway.marvel.com/v1/public/characters/1009351",
+					"comics": {
+						"available": 1661,
+						"collectionURI": "http://gateway.marvel.com/v1/public/characters/1009351/comics",
+						"items": [
+							{"resourceURI": "http://gateway.marvel.com/v1/public/comics/41112", "name": "5 Ronin (Marvel Edition) (2011) #2"}
+						],
+						"returned": 1
+					},
+					"stories": {
+						"available": 2499,
+						"collectionURI": "http://gateway.marvel.com/v1/public/characters/1009351/stories",
+						"items": [
+							{"resourceURI": "http://gateway.marvel.com/v1/public/stories/702", "name": "INCREDIBLE HULK (1999) #35", "type": "cover"}
+						],
+						"returned": 1
+					},
+					"urls": [
+						{"type": "detail", "url": "http://marvel.com/characters/25/hulk"},
+						{"type": "wiki", "url": "http://marvel.com/universe/Hulk_(Bruce_Banner)"}
+					]
+				}
+			]
+		}
+	}
+	`
+	ts := testServer("/v1/public/characters/{characterId}", http.StatusOK, testResponse)
+	defer ts.Close()
+
+	client := marvel.NewClient(testCfg(ts.URL), logging.Discard())
+
+	// when
+	charData, err := client.GetCharacter(context.Background(), 1009351)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, "http://gateway.marvel.com/v1/public/characters/1009351", charData.ResourceURI)
+	assert.Equal(t, &marvel.MarvelApiImage{Path: "http://i.annihil.us/u/prod/marvel/i/mg/5/a0/538615ca33ab0", Extension: "jpg"}, charData.Thumbnail)
+	assert.Equal(t, 1661, charData.Comics.Available)
+	assert.Equal(t, "5 Ronin (Marvel Edition) (2011) #2", charData.Comics.Items[0].Name)
+	assert.Equal(t, "cover", charData.Stories.Items[0].Type)
+	assert.Nil(t, charData.Series)
+	assert.Equal(t, []*marvel.Link{
+		{Type: "detail", Url: "http://marvel.com/characters/25/hulk"},
+		{Type: "wiki", Url: "http://marvel.com/universe/Hulk_(Bruce_Banner)"},
+	}, charData.Urls)
+}
+
 func Test_Client_GetCharacter_404(t *testing.T) {
 	// given
 	testResponse := `
@@ -130,12 +207,578 @@ func Test_Client_GetCharacter_404(t *testing.T) {
 
 	cfg := testCfg(ts.URL)
 
-	client := marvel.NewClient(cfg)
+	client := marvel.NewClient(cfg, logging.Discard())
 
 	// when
-	_, err := client.GetCharacter(9111111)
+	_, err := client.GetCharacter(context.Background(), 9111111)
 
 	// then
 	assert.Error(t, err)
 	assert.IsType(t, new(errs.NotFound), err)
 }
+
+func flakyTestServer(path string, failures int, failStatus int, header http.Header, testResponse string) (*httptest.Server, *int32) {
+	var calls int32
+	r := mux.NewRouter()
+	r.HandleFunc(path, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if int(atomic.AddInt32(&calls, 1)) <= failures {
+			for k, v := range header {
+				w.Header()[k] = v
+			}
+			w.WriteHeader(failStatus)
+			fmt.Fprintln(w, `{"code": "Error", "message": "something went wrong"}`)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintln(w, testResponse)
+	}))
+	return httptest.NewServer(r), &calls
+}
+
+func testRetryPolicy() marvel.RetryPolicy {
+	return marvel.RetryPolicy{
+		MaxAttempts:       3,
+		BaseDelay:         time.Millisecond,
+		MaxDelay:          10 * time.Millisecond,
+		Jitter:            0.5,
+		RetryableStatuses: []int{http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable},
+	}
+}
+
+const hulkTestResponse = `
+{
+	"code": 200,
+	"status": "Ok",
+	"data": {
+		"offset": 0,
+		"limit": 1,
+		"total": 1,
+		"count": 1,
+		"results": [
+			{
+				"id": 1009351,
+				"name": "Hulk",
+				"description": "",
+				"modified": "2020-07-21T10:35:15-0400"
+			}
+		]
+	}
+}
+`
+
+func Test_Client_GetCharacter_RetriesUntilSuccess(t *testing.T) {
+	// given
+	ts, calls := flakyTestServer("/v1/public/characters/{characterId}", 2, http.StatusServiceUnavailable, nil, hulkTestResponse)
+	defer ts.Close()
+
+	cfg := testCfg(ts.URL)
+	cfg.Retry = testRetryPolicy()
+
+	client := marvel.NewClient(cfg, logging.Discard())
+
+	// when
+	charData, err := client.GetCharacter(context.Background(), 1009351)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, 1009351, charData.Id)
+	assert.Equal(t, int32(3), atomic.LoadInt32(calls))
+}
+
+func Test_Client_GetCharacter_RetriesExhausted(t *testing.T) {
+	// given
+	ts, calls := flakyTestServer("/v1/public/characters/{characterId}", 5, http.StatusBadGateway, nil, hulkTestResponse)
+	defer ts.Close()
+
+	cfg := testCfg(ts.URL)
+	cfg.Retry = testRetryPolicy()
+
+	client := marvel.NewClient(cfg, logging.Discard())
+
+	// when
+	_, err := client.GetCharacter(context.Background(), 1009351)
+
+	// then
+	assert.Error(t, err)
+	assert.IsType(t, new(errs.BadGateway), err)
+	assert.Equal(t, int32(3), atomic.LoadInt32(calls))
+}
+
+func Test_Client_GetCharacter_404NotRetried(t *testing.T) {
+	// given
+	ts, calls := flakyTestServer("/v1/public/characters/{characterId}", 5, http.StatusNotFound, nil, hulkTestResponse)
+	defer ts.Close()
+
+	cfg := testCfg(ts.URL)
+	cfg.Retry = testRetryPolicy()
+	cfg.Retry.RetryableStatuses = append(cfg.Retry.RetryableStatuses, http.StatusNotFound)
+
+	client := marvel.NewClient(cfg, logging.Discard())
+
+	// when
+	_, err := client.GetCharacter(context.Background(), 9111111)
+
+	// then
+	assert.Error(t, err)
+	assert.IsType(t, new(errs.NotFound), err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(calls))
+}
+
+func Test_Client_GetCharacter_RetryAfterExceedingMaxDelay(t *testing.T) {
+	// given
+	header := http.Header{"Retry-After": []string{"60"}}
+	ts, calls := flakyTestServer("/v1/public/characters/{characterId}", 1, http.StatusTooManyRequests, header, hulkTestResponse)
+	defer ts.Close()
+
+	cfg := testCfg(ts.URL)
+	cfg.Retry = testRetryPolicy()
+
+	client := marvel.NewClient(cfg, logging.Discard())
+
+	// when
+	_, err := client.GetCharacter(context.Background(), 1009351)
+
+	// then
+	assert.Error(t, err)
+	assert.IsType(t, new(errs.BadGateway), err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(calls))
+}
+
+func Test_Client_GetCharacter_RetryAfterRespected(t *testing.T) {
+	// given
+	header := http.Header{"Retry-After": []string{"1"}}
+	ts, calls := flakyTestServer("/v1/public/characters/{characterId}", 1, http.StatusTooManyRequests, header, hulkTestResponse)
+	defer ts.Close()
+
+	cfg := testCfg(ts.URL)
+	cfg.Retry = testRetryPolicy()
+	cfg.Retry.MaxDelay = 2 * time.Second
+
+	client := marvel.NewClient(cfg, logging.Discard())
+
+	// when
+	start := time.Now()
+	_, err := client.GetCharacter(context.Background(), 1009351)
+
+	// then
+	assert.NoError(t, err)
+	assert.GreaterOrEqual(t, int64(time.Since(start)), int64(time.Second))
+	assert.Equal(t, int32(2), atomic.LoadInt32(calls))
+}
+
+func Test_Client_GetAllCharacters_BoundedConcurrency(t *testing.T) {
+	// given
+	total := 10
+	var inFlight, maxInFlight int32
+
+	r := mux.NewRouter()
+	r.HandleFunc("/v1/public/characters", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		current := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			max := atomic.LoadInt32(&maxInFlight)
+			if current <= max || atomic.CompareAndSwapInt32(&maxInFlight, max, current) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		var results []string
+		for id := offset; id < offset+limit && id < total; id++ {
+			results = append(results, fmt.Sprintf(`{"id": %d, "name": "char %d", "modified": "2014-04-29T14:18:17-0400"}`, id, id))
+		}
+		fmt.Fprintf(w, `{"data": {"offset": %d, "limit": %d, "total": %d, "count": %d, "results": [%s]}}`,
+			offset, limit, total, len(results), strings.Join(results, ","))
+	}))
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	cfg := testCfg(ts.URL)
+	cfg.MaxConcurrentRequests = 2
+	cfg.PageSize = 2
+
+	client := marvel.NewClient(cfg, logging.Discard())
+
+	// when
+	chars, err := client.GetAllCharacters(context.Background(), nil)
+
+	// then
+	assert.NoError(t, err)
+	assert.Len(t, chars, total)
+	assert.LessOrEqual(t, atomic.LoadInt32(&maxInFlight), int32(2))
+}
+
+func Test_Client_GetCharacter_ContextCancelled(t *testing.T) {
+	// given
+	ts, calls := flakyTestServer("/v1/public/characters/{characterId}", 0, http.StatusOK, nil, hulkTestResponse)
+	defer ts.Close()
+
+	client := marvel.NewClient(testCfg(ts.URL), logging.Discard())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// when
+	_, err := client.GetCharacter(ctx, 1009351)
+
+	// then
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, int32(0), atomic.LoadInt32(calls))
+}
+
+func Test_Client_GetAllCharacters_SiblingPagesCancelledOnError(t *testing.T) {
+	// given
+	r := mux.NewRouter()
+	r.HandleFunc("/v1/public/characters", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("offset") {
+		case "":
+			fmt.Fprintln(w, `{"data": {"offset": 0, "limit": 1, "total": 3, "count": 1, "results": [{"id": 1, "modified": "2014-04-29T14:18:17-0400"}]}}`)
+		case "1":
+			w.WriteHeader(http.StatusBadGateway)
+			fmt.Fprintln(w, `{"code": "Error", "message": "something went wrong"}`)
+		default:
+			select {
+			case <-r.Context().Done():
+			case <-time.After(5 * time.Second):
+			}
+		}
+	}))
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	cfg := testCfg(ts.URL)
+	cfg.PageSize = 1
+
+	client := marvel.NewClient(cfg, logging.Discard())
+
+	// when
+	start := time.Now()
+	_, err := client.GetAllCharacters(context.Background(), nil)
+
+	// then
+	assert.Error(t, err)
+	assert.IsType(t, new(errs.BadGateway), err)
+	assert.Less(t, int64(time.Since(start)), int64(time.Second))
+}
+
+func Test_Client_GetCharacter_BudgetExhausted(t *testing.T) {
+	// given
+	ts, calls := flakyTestServer("/v1/public/characters/{characterId}", 0, http.StatusOK, nil, hulkTestResponse)
+	defer ts.Close()
+
+	cfg := testCfg(ts.URL)
+	cfg.Budget = marvel.CallBudgetConfig{Limit: 3, Reserve: 1}
+
+	client := marvel.NewClient(cfg, logging.Discard())
+
+	// when
+	_, err1 := client.GetCharacter(context.Background(), 1009351)
+	_, err2 := client.GetCharacter(context.Background(), 1009351)
+	_, err3 := client.GetCharacter(context.Background(), 1009351)
+
+	// then
+	assert.NoError(t, err1)
+	assert.NoError(t, err2)
+	assert.Error(t, err3)
+	assert.IsType(t, new(errs.ServiceUnavailable), err3)
+	assert.Equal(t, int32(2), atomic.LoadInt32(calls))
+
+	status := client.Budget().Status()
+	assert.Equal(t, 2, status.Used)
+	assert.Equal(t, 1, status.Remaining)
+}
+
+func Test_CallBudget_ResetsOnSchedule(t *testing.T) {
+	// given
+	budget := marvel.NewCallBudget(marvel.CallBudgetConfig{Limit: 1, ResetInterval: 100 * time.Millisecond})
+	for budget.Take() {
+		// exhaust the budget (it may be replenished once if a reset falls in between)
+	}
+	assert.Equal(t, 0, budget.Status().Remaining)
+
+	// when
+	time.Sleep(110 * time.Millisecond)
+
+	// then
+	assert.True(t, budget.Take())
+}
+
+func Test_Client_GetCharacter_NotModifiedServedFromStoredResponse(t *testing.T) {
+	// given
+	etag := "f0fbae65eb2f8f28bdeea0a29be8749a4e67acb3"
+	var ifNoneMatch []string
+
+	r := mux.NewRouter()
+	r.HandleFunc("/v1/public/characters/{characterId}", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ifNoneMatch = append(ifNoneMatch, r.Header.Get("If-None-Match"))
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		fmt.Fprintf(w, `{"etag": "%s", "data": {"total": 1, "count": 1, "results": [{"id": 1009351, "name": "Hulk"}]}}`, etag)
+	}))
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	client := marvel.NewClient(testCfg(ts.URL), logging.Discard())
+
+	// when
+	first, err1 := client.GetCharacter(context.Background(), 1009351)
+	second, err2 := client.GetCharacter(context.Background(), 1009351)
+
+	// then
+	assert.NoError(t, err1)
+	assert.NoError(t, err2)
+	assert.Equal(t, []string{"", etag}, ifNoneMatch)
+	assert.Equal(t, first, second)
+}
+
+func Test_Client_GetAllCharacters_NotModifiedServedFromStoredResponse(t *testing.T) {
+	// given
+	etag := "f0fbae65eb2f8f28bdeea0a29be8749a4e67acb3"
+	var ifNoneMatch []string
+
+	r := mux.NewRouter()
+	r.HandleFunc("/v1/public/characters", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ifNoneMatch = append(ifNoneMatch, r.Header.Get("If-None-Match"))
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		fmt.Fprintf(w, `{"etag": "%s", "data": {"total": 1, "count": 1, "results": [{"id": 1009351, "name": "Hulk"}]}}`, etag)
+	}))
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	client := marvel.NewClient(testCfg(ts.URL), logging.Discard())
+
+	// when
+	first, err1 := client.GetAllCharacters(context.Background(), nil)
+	second, err2 := client.GetAllCharacters(context.Background(), nil)
+
+	// then
+	assert.NoError(t, err1)
+	assert.NoError(t, err2)
+	assert.Equal(t, []string{"", etag}, ifNoneMatch)
+	assert.Equal(t, first, second)
+	assert.Len(t, second, 1)
+}
+
+func Test_Client_SearchCharacters_HappyPath(t *testing.T) {
+	// given
+	var query url.Values
+
+	r := mux.NewRouter()
+	r.HandleFunc("/v1/public/characters", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		fmt.Fprintln(w, `{"data": {"offset": 20, "limit": 20, "total": 21, "count": 1, "results": [{"id": 1009610, "name": "Spider-Man"}]}}`)
+	}))
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	client := marvel.NewClient(testCfg(ts.URL), logging.Discard())
+
+	// when
+	chars, total, err := client.SearchCharacters(context.Background(), marvel.CharacterSearch{
+		NameStartsWith: "spider",
+		Limit:          20,
+		Offset:         20,
+	})
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, 21, total)
+	assert.Len(t, chars, 1)
+	assert.Equal(t, "spider", query.Get("nameStartsWith"))
+	assert.Equal(t, "", query.Get("name"))
+	assert.Equal(t, "20", query.Get("offset"))
+	assert.Equal(t, "name", query.Get("orderBy"))
+}
+
+func Test_Client_GetComics_HappyPath(t *testing.T) {
+	// given
+	var query url.Values
+
+	r := mux.NewRouter()
+	r.HandleFunc("/v1/public/comics", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		fmt.Fprintln(w, `{"data": {"offset": 40, "limit": 20, "total": 48000, "count": 1, "results": [
+			{"id": 41112, "title": "5 Ronin (Marvel Edition) (2011) #2", "issueNumber": 2, "format": "Comic",
+			 "prices": [{"type": "printPrice", "price": 2.99}], "dates": [{"type": "onsaleDate", "date": "2011-03-02T00:00:00-0500"}]}
+		]}}`)
+	}))
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	client := marvel.NewClient(testCfg(ts.URL), logging.Discard())
+
+	// when
+	comics, total, err := client.GetComics(context.Background(), marvel.ResourceQuery{Limit: 20, Offset: 40})
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, 48000, total)
+	assert.Equal(t, "20", query.Get("limit"))
+	assert.Equal(t, "40", query.Get("offset"))
+	assert.NotEmpty(t, query.Get("hash"))
+	if assert.Len(t, comics, 1) {
+		assert.Equal(t, "5 Ronin (Marvel Edition) (2011) #2", comics[0].Title)
+		assert.Equal(t, float64(2), comics[0].IssueNumber)
+		assert.Equal(t, []*marvel.Price{{Type: "printPrice", Price: 2.99}}, comics[0].Prices)
+		assert.Equal(t, []*marvel.MarvelApiDate{{Type: "onsaleDate", Date: "2011-03-02T00:00:00-0500"}}, comics[0].Dates)
+	}
+}
+
+func Test_Client_GetEvent_404(t *testing.T) {
+	// given
+	ts := testServer("/v1/public/events/{eventId}", http.StatusNotFound, `{"code": 404, "status": "We couldn't find that event_id"}`)
+	defer ts.Close()
+
+	client := marvel.NewClient(testCfg(ts.URL), logging.Discard())
+
+	// when
+	event, err := client.GetEvent(context.Background(), 1)
+
+	// then
+	assert.Nil(t, event)
+	assert.IsType(t, &errs.NotFound{}, err)
+}
+
+func Test_Client_GetCharacterComics_FiltersPassedThrough(t *testing.T) {
+	// given
+	var query url.Values
+
+	r := mux.NewRouter()
+	r.HandleFunc("/v1/public/characters/{characterId}/comics", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "1009351", mux.Vars(r)["characterId"])
+		query = r.URL.Query()
+		fmt.Fprintln(w, `{"data": {"offset": 0, "limit": 10, "total": 1, "count": 1, "results": [{"id": 41112, "title": "5 Ronin (Marvel Edition) (2011) #2"}]}}`)
+	}))
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	client := marvel.NewClient(testCfg(ts.URL), logging.Discard())
+
+	// when
+	comics, total, err := client.GetCharacterComics(context.Background(), 1009351, marvel.ResourceQuery{
+		Limit:     10,
+		OrderBy:   "-onsaleDate",
+		Format:    "trade paperback",
+		DateRange: "2010-01-01,2012-12-31",
+	})
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, 1, total)
+	assert.Len(t, comics, 1)
+	assert.Equal(t, "10", query.Get("limit"))
+	assert.Equal(t, "-onsaleDate", query.Get("orderBy"))
+	assert.Equal(t, "trade paperback", query.Get("format"))
+	assert.Equal(t, "2010-01-01,2012-12-31", query.Get("dateRange"))
+}
+
+func Test_Client_GetComicCharactersPage_HappyPath(t *testing.T) {
+	// given
+	var query url.Values
+
+	r := mux.NewRouter()
+	r.HandleFunc("/v1/public/comics", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		fmt.Fprintln(w, `{"data": {"offset": 0, "limit": 100, "total": 1, "count": 1, "results": [
+			{"id": 41112, "modified": "2021-03-01T10:00:00-0500", "characters": {"available": 2, "returned": 2, "items": [
+				{"resourceURI": "http://gateway.marvel.com/v1/public/characters/1009351", "name": "Hulk"},
+				{"resourceURI": "http://gateway.marvel.com/v1/public/characters/1009610", "name": "Spider-Man"}
+			]}}
+		]}}`)
+	}))
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	client := marvel.NewClient(testCfg(ts.URL), logging.Discard())
+
+	// when
+	comicChars, total, err := client.GetComicCharactersPage(context.Background(), nil, 100)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, 1, total)
+	assert.Equal(t, "true", query.Get("noVariants"))
+	assert.Equal(t, "-modified", query.Get("orderBy"))
+	assert.Equal(t, "100", query.Get("offset"))
+	assert.Equal(t, []*marvel.ComicCharacters{
+		{ComicId: 41112, Modified: "2021-03-01T10:00:00-0500", CharacterIds: []int{1009351, 1009610}},
+	}, comicChars)
+}
+
+func Test_Client_GetComicCharactersPage_ResponsesNotStoredForConditionalRequests(t *testing.T) {
+	// given
+	var ifNoneMatch []string
+
+	r := mux.NewRouter()
+	r.HandleFunc("/v1/public/comics", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ifNoneMatch = append(ifNoneMatch, r.Header.Get("If-None-Match"))
+		fmt.Fprintln(w, `{"etag": "f0fbae65eb2f8f28bdeea0a29be8749a4e67acb3", "data": {"total": 1, "count": 1, "results": [{"id": 41112}]}}`)
+	}))
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	client := marvel.NewClient(testCfg(ts.URL), logging.Discard())
+
+	// when
+	_, _, err1 := client.GetComicCharactersPage(context.Background(), nil, 0)
+	_, _, err2 := client.GetComicCharactersPage(context.Background(), nil, 0)
+
+	// then
+	assert.NoError(t, err1)
+	assert.NoError(t, err2)
+	assert.Equal(t, []string{"", ""}, ifNoneMatch)
+}
+
+func Test_Client_GetComic_RecordsMetrics(t *testing.T) {
+	// given
+	ts := testServer("/v1/public/comics/{comicId}", http.StatusNotFound, `{"code": 404, "status": "We couldn't find that comic"}`)
+	defer ts.Close()
+
+	cfg := testCfg(ts.URL)
+
+	client := marvel.NewClient(cfg, logging.Discard())
+
+	requestsSeries := `marvel_api_requests_total{endpoint="/v1/public/comics/{id}",status="404"}`
+	durationSeries := `marvel_api_request_duration_seconds_count{endpoint="/v1/public/comics/{id}"}`
+	before := scrapeMetrics()
+
+	// when
+	_, err := client.GetComic(context.Background(), 4242)
+
+	// then
+	assert.Error(t, err)
+	after := scrapeMetrics()
+	assert.Equal(t, 1.0, after[requestsSeries]-before[requestsSeries])
+	assert.Equal(t, 1.0, after[durationSeries]-before[durationSeries])
+}
+
+func Test_Client_GetCharacter_CredentialsRedacted(t *testing.T) {
+	// given
+	ts := testServer("/v1/public/characters/{characterId}", http.StatusOK, `{"code": 200}`)
+	ts.Close() // calls fail to connect
+
+	cfg := testCfg(ts.URL)
+
+	var buf bytes.Buffer
+	client := marvel.NewClient(cfg, logging.New(&buf, logging.LevelDebug))
+
+	ctx := logging.WithRequestId(context.Background(), "req-1")
+
+	// when
+	_, err := client.GetCharacter(ctx, 1009351)
+
+	// then
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "hash=REDACTED")
+	assert.Contains(t, buf.String(), `"requestId":"req-1"`)
+	assert.Contains(t, buf.String(), "apikey=REDACTED")
+	assert.NotContains(t, buf.String(), "apikey="+cfg.ApiKeyPublic)
+	assert.NotRegexp(t, `hash=[0-9a-f]{32}`, buf.String())
+}
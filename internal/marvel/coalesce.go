@@ -0,0 +1,90 @@
+package marvel
+
+import (
+	"context"
+	"sync"
+
+	"github.com/gkatanacio/marvel-characters-api/internal/logging"
+)
+
+// inFlightCall is a call shared by all the callers of callGroup.Do using the same key.
+type inFlightCall struct {
+	done    chan struct{}
+	val     interface{}
+	err     error
+	waiters int
+	cancel  context.CancelFunc
+}
+
+// callGroup coalesces concurrent calls with the same key, so that the underlying
+// function runs once and all callers receive its result. The shared call is only
+// cancelled when every caller waiting on it has gone away.
+type callGroup struct {
+	sync.Mutex
+	calls map[string]*inFlightCall
+}
+
+func newCallGroup() *callGroup {
+	return &callGroup{
+		calls: make(map[string]*inFlightCall),
+	}
+}
+
+// Do runs `fn` for the given key, unless a call for the same key is already in flight,
+// in which case it waits for that call's result instead. The shared call is logged as
+// part of the request of the caller that started it.
+func (g *callGroup) Do(ctx context.Context, key string, fn func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+	g.Lock()
+	call, exists := g.calls[key]
+	if !exists {
+		callCtx, cancel := context.WithCancel(detachedContext(ctx))
+		call = &inFlightCall{
+			done:   make(chan struct{}),
+			cancel: cancel,
+		}
+		g.calls[key] = call
+
+		go func() {
+			call.val, call.err = fn(callCtx)
+			g.forget(key, call)
+			cancel()
+			close(call.done)
+		}()
+	}
+	call.waiters++
+	g.Unlock()
+
+	select {
+	case <-call.done:
+		return call.val, call.err
+	case <-ctx.Done():
+		g.Lock()
+		call.waiters--
+		if call.waiters == 0 {
+			// nobody is interested in the result anymore
+			call.cancel()
+			if g.calls[key] == call {
+				delete(g.calls, key)
+			}
+		}
+		g.Unlock()
+		return nil, ctx.Err()
+	}
+}
+
+// detachedContext returns a context which is not cancelled along with `ctx`,
+// but still belongs to the same request.
+func detachedContext(ctx context.Context) context.Context {
+	if id := logging.RequestId(ctx); id != "" {
+		return logging.WithRequestId(context.Background(), id)
+	}
+	return context.Background()
+}
+
+func (g *callGroup) forget(key string, call *inFlightCall) {
+	g.Lock()
+	defer g.Unlock()
+	if g.calls[key] == call {
+		delete(g.calls, key)
+	}
+}
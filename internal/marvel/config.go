@@ -1,6 +1,9 @@
 package marvel
 
 import (
+	"time"
+
+	"github.com/gkatanacio/marvel-characters-api/internal/logging"
 	"github.com/kelseyhightower/envconfig"
 )
 
@@ -9,7 +12,41 @@ type Config struct {
 	ApiKeyPublic  string `envconfig:"MARVEL_API_KEY_PUBLIC"`
 	ApiKeyPrivate string `envconfig:"MARVEL_API_KEY_PRIVATE"`
 
-	EagerLoadCache bool `envconfig:"EAGER_LOAD_CACHE"`
+	// MaxConcurrentRequests limits the number of simultaneous calls to Marvel's API.
+	// Zero means no limit.
+	MaxConcurrentRequests int `envconfig:"MARVEL_MAX_CONCURRENT_REQUESTS" default:"4"`
+	// PageSize is the number of results requested per call when paginating
+	// through Marvel's API (maximum of 100).
+	PageSize int `envconfig:"MARVEL_PAGE_SIZE" default:"100"`
+
+	Retry  RetryPolicy      `envconfig:"MARVEL_RETRY"`
+	Budget CallBudgetConfig `envconfig:"MARVEL_CALL_BUDGET"`
+
+	EagerLoadCache bool               `envconfig:"EAGER_LOAD_CACHE"`
+	DetailCache    DetailCacheConfig  `envconfig:"CHARACTER_CACHE"`
+	RelatedCache   RelatedCacheConfig `envconfig:"CHARACTER_RELATED_CACHE"`
+	Refresh        RefreshConfig      `envconfig:"CACHE_REFRESH"`
+	// BatchConcurrency limits the number of characters of a batch lookup fetched at the same time.
+	// Zero means no limit.
+	BatchConcurrency int `envconfig:"CHARACTER_BATCH_CONCURRENCY" default:"8"`
+	// MaxStaleness is how long after the last successful sync cached data is still served
+	// while syncing fails. Zero means no limit.
+	MaxStaleness time.Duration `envconfig:"CACHE_MAX_STALENESS" default:"1h"`
+	// ReconcileMinRatio is the minimum size of the complete list of characters from Marvel's API,
+	// relative to the number of cached characters, for a reconciliation to be applied.
+	ReconcileMinRatio float64 `envconfig:"CACHE_RECONCILE_MIN_RATIO" default:"0.9"`
+	// ChangeLogSize is the maximum number of changes to the characters kept for the changes feed.
+	// Zero means no limit.
+	ChangeLogSize int `envconfig:"CACHE_CHANGE_LOG_SIZE" default:"10000"`
+	// Stream configures the streams of changes to the characters (/characters/stream).
+	Stream   StreamConfig  `envconfig:"CHANGE_STREAM"`
+	Webhooks WebhookConfig `envconfig:"WEBHOOK"`
+
+	// SnapshotPath is the file where the cache is persisted across restarts. Empty disables it.
+	SnapshotPath string `envconfig:"CACHE_SNAPSHOT_PATH"`
+
+	// LogLevel is the minimum level of the lines logged (debug, info, warn or error).
+	LogLevel string `envconfig:"LOG_LEVEL" default:"info"`
 }
 
 func NewConfig() *Config {
@@ -31,4 +68,26 @@ func (c *Config) validate() {
 			panic("environment variables not properly configured")
 		}
 	}
+
+	if _, err := time.Parse("15:04", c.Budget.ResetTime); err != nil {
+		panic("MARVEL_CALL_BUDGET_RESET_TIME must be in HH:MM format")
+	}
+
+	if len(c.Webhooks.Urls) > 0 && c.Webhooks.Secret == "" {
+		panic("WEBHOOK_SECRET must be set along with WEBHOOK_URLS")
+	}
+
+	if _, err := logging.ParseLevel(c.LogLevel); err != nil {
+		panic("LOG_LEVEL must be one of debug, info, warn or error")
+	}
+}
+
+// maxPageSize is the maximum `limit` accepted by Marvel's API.
+const maxPageSize = 100
+
+func (c *Config) pageSize() int {
+	if c.PageSize < 1 || c.PageSize > maxPageSize {
+		return maxPageSize
+	}
+	return c.PageSize
 }
@@ -0,0 +1,132 @@
+package marvel
+
+import (
+	"time"
+)
+
+// CharacterDetailCache is the interface for caching the details of individual Marvel characters.
+type CharacterDetailCache interface {
+	SetCharacter(char *Character)
+	SetCharacterUntil(char *Character, expiresAt time.Time)
+	SetCharacterNotFound(id int)
+	GetCharacter(id int) (*Character, bool)
+	GetAllCharacters() []*Character
+	GetAllEntries() []*CachedCharacter
+	RemoveCharacter(id int)
+	Len() int
+}
+
+// DetailCacheConfig configures the caching of individual character details.
+type DetailCacheConfig struct {
+	// TTL is how long a character is cached. Zero disables caching of characters.
+	TTL time.Duration `envconfig:"TTL" default:"1h"`
+	// NotFoundTTL is how long a character ID unknown to Marvel's API is cached.
+	// Zero disables caching of unknown IDs.
+	NotFoundTTL time.Duration `envconfig:"NOT_FOUND_TTL" default:"5m"`
+	// MaxEntries is the maximum number of cached entries, after which the least
+	// recently used entries are evicted. Zero means no limit.
+	MaxEntries int `envconfig:"MAX_ENTRIES" default:"1000"`
+}
+
+// CachedCharacter is a cached character along with the time it expires from the cache.
+type CachedCharacter struct {
+	Character *Character `json:"character"`
+	ExpiresAt time.Time  `json:"expiresAt"`
+}
+
+// InMemCharacterDetailCache is the in-memory (LRU) implementation of CharacterDetailCache.
+type InMemCharacterDetailCache struct {
+	cfg     DetailCacheConfig
+	entries *lruCache // character IDs to characters, nil if the character was not found
+}
+
+func NewInMemDetailCache(cfg DetailCacheConfig) *InMemCharacterDetailCache {
+	return &InMemCharacterDetailCache{
+		cfg:     cfg,
+		entries: newLruCache(cfg.MaxEntries),
+	}
+}
+
+// SetCharacter caches `char` for the configured TTL.
+func (c *InMemCharacterDetailCache) SetCharacter(char *Character) {
+	if c.cfg.TTL <= 0 {
+		return
+	}
+	charCopy := *char
+	c.entries.set(char.Id, &charCopy, c.cfg.TTL)
+}
+
+// SetCharacterUntil caches `char` until `expiresAt`, e.g. to restore a previously cached
+// character without extending its lifetime. It is never cached for longer than the configured TTL.
+func (c *InMemCharacterDetailCache) SetCharacterUntil(char *Character, expiresAt time.Time) {
+	ttl := time.Until(expiresAt)
+	if ttl > c.cfg.TTL {
+		ttl = c.cfg.TTL
+	}
+	if ttl <= 0 {
+		return
+	}
+	charCopy := *char
+	c.entries.set(char.Id, &charCopy, ttl)
+}
+
+// SetCharacterNotFound caches the absence of a character with the given ID
+// for the configured (usually shorter) TTL.
+func (c *InMemCharacterDetailCache) SetCharacterNotFound(id int) {
+	if c.cfg.NotFoundTTL <= 0 {
+		return
+	}
+	c.entries.set(id, (*Character)(nil), c.cfg.NotFoundTTL)
+}
+
+// GetCharacter returns the cached character with the given ID. The returned bool is false
+// on a cache miss. A character cached as not found is returned as nil with true.
+func (c *InMemCharacterDetailCache) GetCharacter(id int) (*Character, bool) {
+	value, ok := c.entries.get(id)
+	if !ok {
+		return nil, false
+	}
+
+	char := value.(*Character)
+	if char == nil {
+		return nil, true
+	}
+	charCopy := *char
+	return &charCopy, true
+}
+
+// GetAllCharacters returns all the cached (and not yet expired) characters,
+// from the most to the least recently used.
+func (c *InMemCharacterDetailCache) GetAllCharacters() []*Character {
+	entries := c.GetAllEntries()
+	chars := make([]*Character, 0, len(entries))
+	for _, entry := range entries {
+		chars = append(chars, entry.Character)
+	}
+	return chars
+}
+
+// GetAllEntries returns all the cached (and not yet expired) characters along with their
+// expiry, from the most to the least recently used.
+func (c *InMemCharacterDetailCache) GetAllEntries() []*CachedCharacter {
+	entries := []*CachedCharacter{}
+	for _, entry := range c.entries.all() {
+		char := entry.value.(*Character)
+		if char == nil {
+			continue
+		}
+		charCopy := *char
+		entries = append(entries, &CachedCharacter{Character: &charCopy, ExpiresAt: entry.expiresAt})
+	}
+	return entries
+}
+
+// RemoveCharacter drops the cached character with the given ID (or its cached absence), if any.
+func (c *InMemCharacterDetailCache) RemoveCharacter(id int) {
+	c.entries.remove(id)
+}
+
+// Len returns the number of cached entries (including expired ones not yet evicted).
+func (c *InMemCharacterDetailCache) Len() int {
+	return c.entries.len()
+}
@@ -0,0 +1,68 @@
+package marvel_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gkatanacio/marvel-characters-api/internal/marvel"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_InMemCharacterDetailCache_Expiry(t *testing.T) {
+	// given
+	cache := marvel.NewInMemDetailCache(marvel.DetailCacheConfig{
+		TTL:         time.Minute,
+		NotFoundTTL: 10 * time.Millisecond,
+	})
+	cache.SetCharacter(&marvel.Character{Id: 1009351, Name: "Hulk"})
+	cache.SetCharacterNotFound(9111111)
+
+	// when
+	time.Sleep(20 * time.Millisecond)
+	hulk, hulkCached := cache.GetCharacter(1009351)
+	_, unknownCached := cache.GetCharacter(9111111)
+
+	// then
+	assert.True(t, hulkCached)
+	assert.Equal(t, "Hulk", hulk.Name)
+	assert.False(t, unknownCached)
+}
+
+func Test_InMemCharacterDetailCache_LeastRecentlyUsedEvicted(t *testing.T) {
+	// given
+	cache := marvel.NewInMemDetailCache(marvel.DetailCacheConfig{
+		TTL:        time.Minute,
+		MaxEntries: 2,
+	})
+	cache.SetCharacter(&marvel.Character{Id: 1})
+	cache.SetCharacter(&marvel.Character{Id: 2})
+	cache.GetCharacter(1)
+
+	// when
+	cache.SetCharacter(&marvel.Character{Id: 3})
+
+	// then
+	_, cached1 := cache.GetCharacter(1)
+	_, cached2 := cache.GetCharacter(2)
+	_, cached3 := cache.GetCharacter(3)
+	assert.True(t, cached1)
+	assert.False(t, cached2)
+	assert.True(t, cached3)
+	assert.Equal(t, 2, cache.Len())
+}
+
+func Test_InMemCharacterDetailCache_SetCharacterUntil(t *testing.T) {
+	// given
+	cache := marvel.NewInMemDetailCache(marvel.DetailCacheConfig{TTL: time.Minute})
+	expiresAt := time.Now().Add(time.Hour)
+
+	// when
+	cache.SetCharacterUntil(&marvel.Character{Id: 1009351, Name: "Hulk"}, expiresAt)
+	cache.SetCharacterUntil(&marvel.Character{Id: 1009610, Name: "Spider-Man"}, time.Now().Add(-time.Second))
+
+	// then
+	entries := cache.GetAllEntries()
+	assert.Len(t, entries, 1)
+	assert.Equal(t, "Hulk", entries[0].Character.Name)
+	assert.True(t, entries[0].ExpiresAt.Before(expiresAt), "expiry is capped at the configured TTL")
+}
@@ -0,0 +1,59 @@
+package marvel
+
+import (
+	"net/url"
+	"sync"
+)
+
+// maxEtagEntries bounds the number of responses remembered for conditional requests.
+const maxEtagEntries = 5000
+
+type etagEntry struct {
+	etag string
+	body []byte
+}
+
+// etagStore remembers the etag and body of responses from Marvel's API, keyed by
+// request URL (without the auth params), so that requests can be made conditional.
+type etagStore struct {
+	sync.RWMutex
+	entries map[string]etagEntry
+}
+
+func newEtagStore() *etagStore {
+	return &etagStore{
+		entries: make(map[string]etagEntry),
+	}
+}
+
+func (s *etagStore) get(key string) (etagEntry, bool) {
+	s.RLock()
+	defer s.RUnlock()
+	e, ok := s.entries[key]
+	return e, ok
+}
+
+func (s *etagStore) set(key string, e etagEntry) {
+	s.Lock()
+	defer s.Unlock()
+
+	if _, exists := s.entries[key]; !exists && len(s.entries) >= maxEtagEntries {
+		// evict an arbitrary entry; worst case is one unconditional request
+		for k := range s.entries {
+			delete(s.entries, k)
+			break
+		}
+	}
+	s.entries[key] = e
+}
+
+// etagKey builds the key for a request from its path and its query params,
+// excluding the auth params (which change on every request).
+func etagKey(path string, queryParams map[string]string) string {
+	q := url.Values{}
+	for k, v := range queryParams {
+		q.Set(k, v)
+	}
+	// url.Values.Encode() sorts by key, so the key is stable
+	return path + "?" + q.Encode()
+}
@@ -0,0 +1,272 @@
+package marvel
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ComicCharacters lists the characters appearing in a comic.
+type ComicCharacters struct {
+	ComicId      int
+	Modified     string
+	CharacterIds []int
+}
+
+// toComicCharacters extracts the IDs of the characters appearing in the comic. Note that Marvel's API
+// lists at most 20 characters per comic, so characters of crowded comics may be missing.
+func (d *MarvelApiComicData) toComicCharacters() *ComicCharacters {
+	cc := &ComicCharacters{ComicId: d.Id, Modified: d.Modified}
+	if d.Characters == nil {
+		return cc
+	}
+
+	for _, item := range d.Characters.Items {
+		if id, ok := idFromResourceURI(item.ResourceURI); ok {
+			cc.CharacterIds = append(cc.CharacterIds, id)
+		}
+	}
+	return cc
+}
+
+// idFromResourceURI extracts the ID of a resource from its URI
+// (e.g. 1009351 from "http://gateway.marvel.com/v1/public/characters/1009351").
+func idFromResourceURI(uri string) (int, bool) {
+	id, err := strconv.Atoi(uri[strings.LastIndex(uri, "/")+1:])
+	if err != nil {
+		return 0, false
+	}
+	return id, true
+}
+
+// Connection is a character appearing in comics together with another one.
+type Connection struct {
+	Id   int    `json:"id"`
+	Name string `json:"name"`
+	// SharedComics is the number of comics the two characters appear in together
+	SharedComics int `json:"sharedComics"`
+}
+
+// CoAppearanceGraph links the characters that appear together in comics. Each pair of linked
+// characters is weighted by the number of comics they share.
+type CoAppearanceGraph struct {
+	sync.RWMutex
+	// comics maps the IDs of the comics to the IDs of the characters appearing in them
+	comics map[int][]int
+	// edges maps the IDs of the characters to the number of comics shared with each linked character
+	edges map[int]map[int]int
+	// appearances maps the IDs of the characters to the number of comics they appear in
+	appearances map[int]int
+	// latestModified is the most recent modification time of the comics in the graph
+	latestModified time.Time
+}
+
+func NewCoAppearanceGraph() *CoAppearanceGraph {
+	return &CoAppearanceGraph{
+		comics:      make(map[int][]int),
+		edges:       make(map[int]map[int]int),
+		appearances: make(map[int]int),
+	}
+}
+
+// Update records the characters of each comic in `comicChars`, replacing whatever was recorded
+// for the same comics before, and moves the latest modification time of the graph forward to `latestModified`.
+func (g *CoAppearanceGraph) Update(comicChars []*ComicCharacters, latestModified time.Time) {
+	g.Lock()
+	defer g.Unlock()
+
+	for _, cc := range comicChars {
+		g.setComic(cc.ComicId, cc.CharacterIds)
+	}
+	if latestModified.After(g.latestModified) {
+		g.latestModified = latestModified
+	}
+}
+
+// LatestModified returns the most recent modification time of the comics in the graph,
+// which is zero if the graph was never built.
+func (g *CoAppearanceGraph) LatestModified() time.Time {
+	g.RLock()
+	defer g.RUnlock()
+	return g.latestModified
+}
+
+// Contains reports whether the character with the given ID appears in any comic of the graph.
+func (g *CoAppearanceGraph) Contains(charId int) bool {
+	g.RLock()
+	defer g.RUnlock()
+	_, exists := g.edges[charId]
+	return exists
+}
+
+// Connections returns the characters sharing comics with the character with the given ID,
+// from the one sharing the most comics to the one sharing the least (then by ID).
+// Only the ID and SharedComics of the connections are set.
+func (g *CoAppearanceGraph) Connections(charId int) []*Connection {
+	g.RLock()
+	defer g.RUnlock()
+
+	conns := make([]*Connection, 0, len(g.edges[charId]))
+	for id, shared := range g.edges[charId] {
+		conns = append(conns, &Connection{Id: id, SharedComics: shared})
+	}
+
+	sort.Slice(conns, func(i, j int) bool {
+		if conns[i].SharedComics != conns[j].SharedComics {
+			return conns[i].SharedComics > conns[j].SharedComics
+		}
+		return conns[i].Id < conns[j].Id
+	})
+
+	return conns
+}
+
+// ShortestPath returns the shortest chain of characters linking `from` to `to` through
+// shared comics, including both ends. The first connection has no SharedComics, the others
+// have the number of comics shared with the previous character in the chain. Only the ID and
+// SharedComics of the connections are set. It returns false if the characters are not linked.
+func (g *CoAppearanceGraph) ShortestPath(from, to int) ([]*Connection, bool) {
+	g.RLock()
+	defer g.RUnlock()
+
+	if _, exists := g.edges[from]; !exists {
+		return nil, false
+	}
+	if from == to {
+		return []*Connection{{Id: from}}, true
+	}
+
+	// breadth-first search, visiting neighbours in ID order so results are deterministic
+	prev := map[int]int{from: from}
+	queue := []int{from}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		neighbours := make([]int, 0, len(g.edges[current]))
+		for id := range g.edges[current] {
+			neighbours = append(neighbours, id)
+		}
+		sort.Ints(neighbours)
+
+		for _, n := range neighbours {
+			if _, visited := prev[n]; visited {
+				continue
+			}
+			prev[n] = current
+			if n == to {
+				return g.buildPath(prev, from, to), true
+			}
+			queue = append(queue, n)
+		}
+	}
+
+	return nil, false
+}
+
+func (g *CoAppearanceGraph) buildPath(prev map[int]int, from, to int) []*Connection {
+	var path []*Connection
+	for id := to; id != from; id = prev[id] {
+		path = append(path, &Connection{Id: id, SharedComics: g.edges[id][prev[id]]})
+	}
+	path = append(path, &Connection{Id: from})
+
+	// reverse, as the path was built from the end
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path
+}
+
+// snapshot returns the characters of each comic in the graph, for persisting it.
+func (g *CoAppearanceGraph) snapshot() *graphSnapshot {
+	g.RLock()
+	defer g.RUnlock()
+
+	comics := make(map[int][]int, len(g.comics))
+	for id, charIds := range g.comics {
+		comics[id] = charIds
+	}
+	return &graphSnapshot{LatestModified: g.latestModified, Comics: comics}
+}
+
+// restore replaces the contents of the graph with those of the snapshot.
+func (g *CoAppearanceGraph) restore(snapshot *graphSnapshot) {
+	g.Lock()
+	defer g.Unlock()
+
+	g.comics = make(map[int][]int, len(snapshot.Comics))
+	g.edges = make(map[int]map[int]int)
+	g.appearances = make(map[int]int)
+	for id, charIds := range snapshot.Comics {
+		g.setComic(id, charIds)
+	}
+	g.latestModified = snapshot.LatestModified
+}
+
+func (g *CoAppearanceGraph) setComic(comicId int, charIds []int) {
+	if oldCharIds, exists := g.comics[comicId]; exists {
+		g.unlink(oldCharIds)
+		delete(g.comics, comicId)
+	}
+
+	charIds = uniqueInts(charIds)
+	if len(charIds) == 0 {
+		return
+	}
+
+	g.comics[comicId] = charIds
+	g.link(charIds)
+}
+
+// link records one more comic shared by each pair of the given characters.
+func (g *CoAppearanceGraph) link(charIds []int) {
+	for _, a := range charIds {
+		g.appearances[a]++
+		if g.edges[a] == nil {
+			g.edges[a] = make(map[int]int)
+		}
+		for _, b := range charIds {
+			if a != b {
+				g.edges[a][b]++
+			}
+		}
+	}
+}
+
+// unlink records one less comic shared by each pair of the given characters, removing
+// the characters that no longer appear in any comic from the graph.
+func (g *CoAppearanceGraph) unlink(charIds []int) {
+	for _, a := range charIds {
+		for _, b := range charIds {
+			if a == b {
+				continue
+			}
+			g.edges[a][b]--
+			if g.edges[a][b] <= 0 {
+				delete(g.edges[a], b)
+			}
+		}
+
+		g.appearances[a]--
+		if g.appearances[a] <= 0 {
+			delete(g.appearances, a)
+			delete(g.edges, a)
+		}
+	}
+}
+
+// uniqueInts returns the distinct values of `ints`, in their original order.
+func uniqueInts(ints []int) []int {
+	seen := make(map[int]bool, len(ints))
+	unique := make([]int, 0, len(ints))
+	for _, i := range ints {
+		if !seen[i] {
+			seen[i] = true
+			unique = append(unique, i)
+		}
+	}
+	return unique
+}
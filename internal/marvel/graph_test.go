@@ -0,0 +1,96 @@
+package marvel_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gkatanacio/marvel-characters-api/internal/marvel"
+	"github.com/stretchr/testify/assert"
+)
+
+func testGraph() *marvel.CoAppearanceGraph {
+	graph := marvel.NewCoAppearanceGraph()
+	graph.Update([]*marvel.ComicCharacters{
+		{ComicId: 1, CharacterIds: []int{10, 20, 30}},
+		{ComicId: 2, CharacterIds: []int{10, 20}},
+		{ComicId: 3, CharacterIds: []int{30, 40}},
+		{ComicId: 4, CharacterIds: []int{50}},
+	}, time.Now())
+	return graph
+}
+
+func Test_CoAppearanceGraph_Connections(t *testing.T) {
+	// given
+	graph := testGraph()
+
+	// when
+	conns := graph.Connections(10)
+
+	// then
+	assert.Equal(t, []*marvel.Connection{
+		{Id: 20, SharedComics: 2},
+		{Id: 30, SharedComics: 1},
+	}, conns)
+	assert.Empty(t, graph.Connections(50))
+}
+
+func Test_CoAppearanceGraph_Update_ReplacesComic(t *testing.T) {
+	// given
+	graph := testGraph()
+
+	// when
+	graph.Update([]*marvel.ComicCharacters{
+		{ComicId: 3, CharacterIds: []int{30, 20}},
+	}, time.Now())
+
+	// then
+	assert.False(t, graph.Contains(40))
+	assert.Equal(t, []*marvel.Connection{
+		{Id: 20, SharedComics: 2},
+		{Id: 10, SharedComics: 1},
+	}, graph.Connections(30))
+}
+
+func Test_CoAppearanceGraph_ShortestPath(t *testing.T) {
+	tests := map[string]struct {
+		from      int
+		to        int
+		expected  []*marvel.Connection
+		connected bool
+	}{
+		"linked through another character": {
+			from:      20,
+			to:        40,
+			expected:  []*marvel.Connection{{Id: 20}, {Id: 30, SharedComics: 1}, {Id: 40, SharedComics: 1}},
+			connected: true,
+		},
+		"same character": {
+			from:      10,
+			to:        10,
+			expected:  []*marvel.Connection{{Id: 10}},
+			connected: true,
+		},
+		"not linked": {
+			from: 10,
+			to:   50,
+		},
+		"unknown character": {
+			from: 99,
+			to:   10,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			// given
+			graph := testGraph()
+
+			// when
+			path, connected := graph.ShortestPath(tc.from, tc.to)
+
+			// then
+			assert.Equal(t, tc.connected, connected)
+			assert.Equal(t, tc.expected, path)
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package marvel
+
+import (
+	"strings"
+)
+
+// ImageVariant is one of the sizes and aspect ratios Marvel's API serves images in.
+// See https://developer.marvel.com/documentation/images.
+type ImageVariant string
+
+const (
+	ImageFullSize ImageVariant = ""
+
+	ImagePortraitSmall      ImageVariant = "portrait_small"
+	ImagePortraitMedium     ImageVariant = "portrait_medium"
+	ImagePortraitXlarge     ImageVariant = "portrait_xlarge"
+	ImagePortraitFantastic  ImageVariant = "portrait_fantastic"
+	ImagePortraitUncanny    ImageVariant = "portrait_uncanny"
+	ImagePortraitIncredible ImageVariant = "portrait_incredible"
+
+	ImageStandardSmall     ImageVariant = "standard_small"
+	ImageStandardMedium    ImageVariant = "standard_medium"
+	ImageStandardLarge     ImageVariant = "standard_large"
+	ImageStandardXlarge    ImageVariant = "standard_xlarge"
+	ImageStandardFantastic ImageVariant = "standard_fantastic"
+	ImageStandardAmazing   ImageVariant = "standard_amazing"
+
+	ImageLandscapeSmall      ImageVariant = "landscape_small"
+	ImageLandscapeMedium     ImageVariant = "landscape_medium"
+	ImageLandscapeLarge      ImageVariant = "landscape_large"
+	ImageLandscapeXlarge     ImageVariant = "landscape_xlarge"
+	ImageLandscapeAmazing    ImageVariant = "landscape_amazing"
+	ImageLandscapeIncredible ImageVariant = "landscape_incredible"
+
+	ImageDetail ImageVariant = "detail"
+)
+
+// ImageVariants are all the valid image variants, other than the full size image.
+var ImageVariants = []ImageVariant{
+	ImagePortraitSmall, ImagePortraitMedium, ImagePortraitXlarge,
+	ImagePortraitFantastic, ImagePortraitUncanny, ImagePortraitIncredible,
+	ImageStandardSmall, ImageStandardMedium, ImageStandardLarge,
+	ImageStandardXlarge, ImageStandardFantastic, ImageStandardAmazing,
+	ImageLandscapeSmall, ImageLandscapeMedium, ImageLandscapeLarge,
+	ImageLandscapeXlarge, ImageLandscapeAmazing, ImageLandscapeIncredible,
+	ImageDetail,
+}
+
+// ParseImageVariant returns the image variant named `s`, or false if there is no such variant.
+// An empty `s` stands for the full size image.
+func ParseImageVariant(s string) (ImageVariant, bool) {
+	if s == "" {
+		return ImageFullSize, true
+	}
+
+	for _, v := range ImageVariants {
+		if string(v) == s {
+			return v, true
+		}
+	}
+	return "", false
+}
+
+// Thumbnail is the image of a resource, hosted by Marvel.
+type Thumbnail struct {
+	Path      string `json:"path"`
+	Extension string `json:"extension"`
+	// Url is the ready-to-use URL of the image, full size unless another variant was requested
+	Url string `json:"url"`
+}
+
+func newThumbnail(path, extension string) *Thumbnail {
+	t := &Thumbnail{Path: path, Extension: extension}
+	t.Url = t.VariantUrl(ImageFullSize)
+	return t
+}
+
+// VariantUrl builds the URL of the image in the given variant.
+func (t *Thumbnail) VariantUrl(variant ImageVariant) string {
+	if variant == ImageFullSize {
+		return t.Path + "." + t.Extension
+	}
+	return strings.TrimSuffix(t.Path, "/") + "/" + string(variant) + "." + t.Extension
+}
+
+// WithVariant returns a copy of the thumbnail whose Url points to the given variant.
+func (t *Thumbnail) WithVariant(variant ImageVariant) *Thumbnail {
+	tCopy := *t
+	tCopy.Url = t.VariantUrl(variant)
+	return &tCopy
+}
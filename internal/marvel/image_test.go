@@ -0,0 +1,51 @@
+package marvel_test
+
+import (
+	"testing"
+
+	"github.com/gkatanacio/marvel-characters-api/internal/marvel"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Thumbnail_VariantUrl(t *testing.T) {
+	// given
+	thumbnail := &marvel.Thumbnail{
+		Path:      "http://i.annihil.us/u/prod/marvel/i/mg/5/a0/538615ca33ab0",
+		Extension: "jpg",
+	}
+
+	// when
+	fullSize := thumbnail.VariantUrl(marvel.ImageFullSize)
+	portrait := thumbnail.VariantUrl(marvel.ImagePortraitXlarge)
+	withVariant := thumbnail.WithVariant(marvel.ImageStandardFantastic)
+
+	// then
+	assert.Equal(t, "http://i.annihil.us/u/prod/marvel/i/mg/5/a0/538615ca33ab0.jpg", fullSize)
+	assert.Equal(t, "http://i.annihil.us/u/prod/marvel/i/mg/5/a0/538615ca33ab0/portrait_xlarge.jpg", portrait)
+	assert.Equal(t, "http://i.annihil.us/u/prod/marvel/i/mg/5/a0/538615ca33ab0/standard_fantastic.jpg", withVariant.Url)
+	assert.Empty(t, thumbnail.Url)
+}
+
+func Test_ParseImageVariant(t *testing.T) {
+	testCases := map[string]struct {
+		input    string
+		expected marvel.ImageVariant
+		valid    bool
+	}{
+		"full size": {"", marvel.ImageFullSize, true},
+		"portrait":  {"portrait_uncanny", marvel.ImagePortraitUncanny, true},
+		"detail":    {"detail", marvel.ImageDetail, true},
+		"unknown":   {"portrait_huge", "", false},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			// when
+			variant, valid := marvel.ParseImageVariant(tc.input)
+
+			// then
+			assert.Equal(t, tc.expected, variant)
+			assert.Equal(t, tc.valid, valid)
+		})
+	}
+}
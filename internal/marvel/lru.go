@@ -0,0 +1,114 @@
+package marvel
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// lruCache is an in-memory cache whose entries expire after a TTL. Once it holds its maximum
+// number of entries, the least recently used ones are evicted. It is the storage shared by
+// the caches of character details and of the resources related to characters.
+type lruCache struct {
+	sync.Mutex
+	maxEntries int        // zero means no limit
+	lru        *list.List // front is the most recently used
+	entries    map[interface{}]*list.Element
+}
+
+// lruEntry is a value cached under a key until it expires.
+type lruEntry struct {
+	key       interface{}
+	value     interface{}
+	expiresAt time.Time
+}
+
+func newLruCache(maxEntries int) *lruCache {
+	return &lruCache{
+		maxEntries: maxEntries,
+		lru:        list.New(),
+		entries:    make(map[interface{}]*list.Element),
+	}
+}
+
+// get returns the value cached under `key`, making it the most recently used entry.
+// The returned bool is false if there is none or it has expired.
+func (c *lruCache) get(key interface{}) (interface{}, bool) {
+	c.Lock()
+	defer c.Unlock()
+
+	elem, exists := c.entries[key]
+	if !exists {
+		return nil, false
+	}
+
+	entry := elem.Value.(*lruEntry)
+	if time.Now().After(entry.expiresAt) {
+		c.removeElement(elem)
+		return nil, false
+	}
+
+	c.lru.MoveToFront(elem)
+	return entry.value, true
+}
+
+// set caches `value` under `key` for `ttl`, making it the most recently used entry.
+func (c *lruCache) set(key, value interface{}, ttl time.Duration) {
+	c.Lock()
+	defer c.Unlock()
+
+	entry := &lruEntry{key: key, value: value, expiresAt: time.Now().Add(ttl)}
+
+	if elem, exists := c.entries[key]; exists {
+		elem.Value = entry
+		c.lru.MoveToFront(elem)
+		return
+	}
+
+	c.entries[key] = c.lru.PushFront(entry)
+
+	if c.maxEntries > 0 {
+		for c.lru.Len() > c.maxEntries {
+			c.removeElement(c.lru.Back())
+		}
+	}
+}
+
+// remove drops the value cached under `key`, if any.
+func (c *lruCache) remove(key interface{}) {
+	c.Lock()
+	defer c.Unlock()
+
+	if elem, exists := c.entries[key]; exists {
+		c.removeElement(elem)
+	}
+}
+
+// all returns the entries that have not expired yet, from the most to the least recently used.
+func (c *lruCache) all() []lruEntry {
+	c.Lock()
+	defer c.Unlock()
+
+	now := time.Now()
+	entries := make([]lruEntry, 0, c.lru.Len())
+	for elem := c.lru.Front(); elem != nil; elem = elem.Next() {
+		entry := elem.Value.(*lruEntry)
+		if now.After(entry.expiresAt) {
+			continue
+		}
+		entries = append(entries, *entry)
+	}
+	return entries
+}
+
+// len returns the number of cached entries (including expired ones not yet evicted).
+func (c *lruCache) len() int {
+	c.Lock()
+	defer c.Unlock()
+	return c.lru.Len()
+}
+
+func (c *lruCache) removeElement(elem *list.Element) {
+	c.lru.Remove(elem)
+	delete(c.entries, elem.Value.(*lruEntry).key)
+}
@@ -0,0 +1,120 @@
+package marvel
+
+import (
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	apiRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "marvel_api_requests_total",
+		Help: "Calls made to Marvel's API, by endpoint and status code (0 if no response was received).",
+	}, []string{"endpoint", "status"})
+	apiRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "marvel_api_request_duration_seconds",
+		Help:    "Latency of the calls made to Marvel's API, by endpoint.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"endpoint"})
+
+	cacheRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "cache_requests_total",
+		Help: "Lookups in the caches, by cache and result (hit or miss).",
+	}, []string{"cache", "result"})
+	cacheSyncs = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "cache_syncs_total",
+		Help: "Syncs of the caches with Marvel's API, by kind and result (success or failure).",
+	}, []string{"kind", "result"})
+	cacheSyncDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "cache_sync_duration_seconds",
+		Help:    "Duration of the syncs of the caches with Marvel's API, by kind.",
+		Buckets: []float64{0.1, 0.5, 1, 5, 10, 30, 60, 120, 300, 600},
+	}, []string{"kind"})
+)
+
+// numericSegment matches the IDs in the paths of Marvel's API.
+var numericSegment = regexp.MustCompile(`/\d+(/|$)`)
+
+// endpointLabel replaces the IDs in a path of Marvel's API with a placeholder,
+// so that calls to the same endpoint share a label value.
+func endpointLabel(path string) string {
+	return numericSegment.ReplaceAllString(path, "/{id}$1")
+}
+
+// observeApiCall records a call to Marvel's API that got a response with the given status code
+// (0 if none was received) after `elapsed`.
+func observeApiCall(path string, status int, elapsed time.Duration) {
+	endpoint := endpointLabel(path)
+	apiRequests.WithLabelValues(endpoint, strconv.Itoa(status)).Inc()
+	apiRequestDuration.WithLabelValues(endpoint).Observe(elapsed.Seconds())
+}
+
+func observeCacheLookup(cache string, hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	cacheRequests.WithLabelValues(cache, result).Inc()
+}
+
+// observeSync records a sync of kind `kind` (e.g. "sync" or "reconcile") that started at `start`.
+func observeSync(kind string, start time.Time, err error) {
+	result := "success"
+	if err != nil {
+		result = "failure"
+	}
+	cacheSyncs.WithLabelValues(kind, result).Inc()
+	cacheSyncDuration.WithLabelValues(kind).Observe(time.Since(start).Seconds())
+}
+
+// RegisterMetrics registers with `registerer` the gauges measuring the state of the caches,
+// which are read every time the metrics are gathered.
+func (s *Service) RegisterMetrics(registerer prometheus.Registerer) {
+	factory := promauto.With(registerer)
+
+	cacheEntries := func(cache string, entries func() int) {
+		factory.NewGaugeFunc(prometheus.GaugeOpts{
+			Name:        "cache_entries",
+			Help:        "Entries held in the caches, by cache.",
+			ConstLabels: prometheus.Labels{"cache": cache},
+		}, func() float64 { return float64(entries()) })
+	}
+	cacheEntries("ids", func() int {
+		charIds, _ := s.cache.GetCharacterIds()
+		return charIds.Len()
+	})
+	cacheEntries("detail", s.detailCache.Len)
+	cacheEntries("related", s.related.len)
+
+	factory.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: "cache_last_successful_sync_timestamp_seconds",
+		Help: "Unix time of the last successful sync of the character IDs with Marvel's API (0 if none yet).",
+	}, func() float64 {
+		lastSuccess := s.SyncStatus().LastSuccess
+		if lastSuccess.IsZero() {
+			return 0
+		}
+		return float64(lastSuccess.Unix())
+	})
+}
+
+// RegisterMetrics registers with `registerer` the gauges measuring the call budget,
+// which are read every time the metrics are gathered.
+func (b *CallBudget) RegisterMetrics(registerer prometheus.Registerer) {
+	factory := promauto.With(registerer)
+
+	factory.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: "marvel_api_budget_limit",
+		Help: "Daily limit of calls to Marvel's API (0 if unlimited).",
+	}, func() float64 { return float64(b.Status().Limit) })
+
+	if b.Status().Limit > 0 {
+		factory.NewGaugeFunc(prometheus.GaugeOpts{
+			Name: "marvel_api_budget_remaining",
+			Help: "Calls to Marvel's API left before the daily limit is reached.",
+		}, func() float64 { return float64(b.Status().Remaining) })
+	}
+}
@@ -1,16 +1,23 @@
 package marvel
 
+import (
+	"encoding/json"
+	"time"
+)
+
 const (
 	dateFormatMarvelApi = "2006-01-02T15:04:05-0700"
 )
 
 type MarvelApiResponse struct {
+	Etag string `json:"etag"`
 	Data struct {
-		Offset  int           `json:"offset"`
-		Limit   int           `json:"limit"`
-		Total   int           `json:"total"`
-		Count   int           `json:"count"`
-		Results []interface{} `json:"results"`
+		Offset int `json:"offset"`
+		Limit  int `json:"limit"`
+		Total  int `json:"total"`
+		Count  int `json:"count"`
+		// Results are decoded according to the type of resource requested
+		Results json.RawMessage `json:"results"`
 	} `json:"data"`
 }
 
@@ -20,14 +27,123 @@ type MarvelApiErrResponse struct {
 }
 
 type MarvelApiCharacterData struct {
-	Id          int    `json:"id"`
-	Name        string `json:"name"`
-	Description string `json:"description"`
-	Modified    string `json:"modified"`
+	Id          int             `json:"id"`
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	Modified    string          `json:"modified"`
+	ResourceURI string          `json:"resourceURI"`
+	Thumbnail   *MarvelApiImage `json:"thumbnail"`
+	Urls        []*Link         `json:"urls"`
+	Comics      *ResourceList   `json:"comics"`
+	Series      *ResourceList   `json:"series"`
+	Stories     *ResourceList   `json:"stories"`
+	Events      *ResourceList   `json:"events"`
+}
+
+type MarvelApiImage struct {
+	Path      string `json:"path"`
+	Extension string `json:"extension"`
+}
+
+func (d *MarvelApiCharacterData) toCharacter() *Character {
+	return &Character{
+		Id:          d.Id,
+		Name:        d.Name,
+		Description: d.Description,
+		Modified:    parseMarvelApiTime(dateFormatMarvelApi, d.Modified),
+		ResourceURI: d.ResourceURI,
+		Thumbnail:   d.Thumbnail.toThumbnail(),
+		Urls:        d.Urls,
+		Comics:      d.Comics,
+		Series:      d.Series,
+		Stories:     d.Stories,
+		Events:      d.Events,
+	}
+}
+
+func toCharacters(data []*MarvelApiCharacterData) []*Character {
+	chars := make([]*Character, 0, len(data))
+	for _, d := range data {
+		chars = append(chars, d.toCharacter())
+	}
+	return chars
 }
 
 type Character struct {
-	Id          int    `json:"id"`
+	Id          int           `json:"id"`
+	Name        string        `json:"name"`
+	Description string        `json:"description"`
+	Modified    *time.Time    `json:"modified,omitempty"`
+	ResourceURI string        `json:"resourceURI,omitempty"`
+	Thumbnail   *Thumbnail    `json:"thumbnail,omitempty"`
+	Urls        []*Link       `json:"urls,omitempty"`
+	Comics      *ResourceList `json:"comics,omitempty"`
+	Series      *ResourceList `json:"series,omitempty"`
+	Stories     *ResourceList `json:"stories,omitempty"`
+	Events      *ResourceList `json:"events,omitempty"`
+}
+
+// Link is a public web page about a resource (e.g. its "detail", "wiki" or "comiclink" page).
+type Link struct {
+	Type string `json:"type"`
+	Url  string `json:"url"`
+}
+
+// ResourceList is a summary of the resources (e.g. comics) related to another resource.
+// Items holds at most 20 of the Available resources; the rest can be fetched from CollectionURI.
+type ResourceList struct {
+	Available     int                `json:"available"`
+	Returned      int                `json:"returned"`
+	CollectionURI string             `json:"collectionURI"`
+	Items         []*ResourceSummary `json:"items"`
+}
+
+type ResourceSummary struct {
+	ResourceURI string `json:"resourceURI"`
 	Name        string `json:"name"`
-	Description string `json:"description"`
+	// Type is only set for stories (e.g. "cover" or "interiorStory")
+	Type string `json:"type,omitempty"`
+	// Role is only set for creators (e.g. "writer" or "penciller")
+	Role string `json:"role,omitempty"`
+}
+
+// CharacterPage is a page of characters, along with the total number of matching characters.
+type CharacterPage struct {
+	Total      int
+	Characters []*Character
+}
+
+// CharacterBatch holds the characters found by a batch lookup, in the order they were requested,
+// along with the errors for the IDs that could not be looked up.
+type CharacterBatch struct {
+	Characters []*Character
+	Errors     []*BatchError
+}
+
+// BatchError is the error of the lookup of a single ID in a batch.
+type BatchError struct {
+	Id     int    `json:"id"`
+	Status int    `json:"status"`
+	Error  string `json:"error"`
+}
+
+// SearchResult is a character matching a search query, along with its relevance score.
+type SearchResult struct {
+	*Character
+	Score float64 `json:"score"`
+}
+
+// SearchPage is a page of search results, along with the total number of results.
+type SearchPage struct {
+	Total   int
+	Results []*SearchResult
+}
+
+// CharacterSearch holds the criteria for searching characters by name.
+// Exactly one of Name and NameStartsWith is expected to be set.
+type CharacterSearch struct {
+	Name           string
+	NameStartsWith string
+	Limit          int
+	Offset         int
 }
@@ -0,0 +1,126 @@
+package marvel
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+// NameIndex indexes characters by name, for case-insensitive
+// lookups by exact name or by name prefix.
+type NameIndex struct {
+	sync.RWMutex
+	chars map[int]*Character
+	// byName holds the characters sorted by lowercase name (then ID), for prefix lookups
+	byName []*Character
+	// complete is true if the index holds every character, rather than just some of them
+	complete bool
+}
+
+func NewNameIndex() *NameIndex {
+	return &NameIndex{
+		chars: make(map[int]*Character),
+	}
+}
+
+// Replace makes the index hold exactly `chars`, which must be the complete list of characters.
+func (ix *NameIndex) Replace(chars []*Character) {
+	ix.Lock()
+	defer ix.Unlock()
+
+	ix.chars = make(map[int]*Character, len(chars))
+	for _, c := range chars {
+		ix.chars[c.Id] = c
+	}
+	ix.complete = true
+	ix.rebuild()
+}
+
+// Upsert adds `chars` to the index, replacing those with the same IDs.
+func (ix *NameIndex) Upsert(chars []*Character) {
+	if len(chars) == 0 {
+		return
+	}
+
+	ix.Lock()
+	defer ix.Unlock()
+
+	for _, c := range chars {
+		ix.chars[c.Id] = c
+	}
+	ix.rebuild()
+}
+
+// Complete reports whether the index holds every character, i.e. whether
+// lookups can be answered from the index alone.
+func (ix *NameIndex) Complete() bool {
+	ix.RLock()
+	defer ix.RUnlock()
+	return ix.complete
+}
+
+// Get returns the indexed character with the given ID, or false if there is none.
+func (ix *NameIndex) Get(id int) (*Character, bool) {
+	ix.RLock()
+	defer ix.RUnlock()
+	c, exists := ix.chars[id]
+	return c, exists
+}
+
+// All returns every indexed character, sorted by name.
+func (ix *NameIndex) All() []*Character {
+	ix.RLock()
+	defer ix.RUnlock()
+	return append([]*Character(nil), ix.byName...)
+}
+
+// FindByName returns the characters whose name is `name`, ignoring case.
+func (ix *NameIndex) FindByName(name string) []*Character {
+	name = strings.ToLower(name)
+
+	ix.RLock()
+	defer ix.RUnlock()
+
+	chars := []*Character{}
+	for i := ix.searchFrom(name); i < len(ix.byName) && strings.ToLower(ix.byName[i].Name) == name; i++ {
+		chars = append(chars, ix.byName[i])
+	}
+	return chars
+}
+
+// FindByNamePrefix returns the characters whose name starts with `prefix`, ignoring case,
+// sorted by name.
+func (ix *NameIndex) FindByNamePrefix(prefix string) []*Character {
+	prefix = strings.ToLower(prefix)
+
+	ix.RLock()
+	defer ix.RUnlock()
+
+	chars := []*Character{}
+	for i := ix.searchFrom(prefix); i < len(ix.byName) && strings.HasPrefix(strings.ToLower(ix.byName[i].Name), prefix); i++ {
+		chars = append(chars, ix.byName[i])
+	}
+	return chars
+}
+
+// searchFrom returns the position of the first character whose lowercase name is not before `s`.
+func (ix *NameIndex) searchFrom(s string) int {
+	return sort.Search(len(ix.byName), func(i int) bool {
+		return strings.ToLower(ix.byName[i].Name) >= s
+	})
+}
+
+func (ix *NameIndex) rebuild() {
+	byName := make([]*Character, 0, len(ix.chars))
+	for _, c := range ix.chars {
+		byName = append(byName, c)
+	}
+	sort.Slice(byName, func(i, j int) bool {
+		ni, nj := strings.ToLower(byName[i].Name), strings.ToLower(byName[j].Name)
+		if ni != nj {
+			return ni < nj
+		}
+		return byName[i].Id < byName[j].Id
+	})
+	ix.byName = byName
+}
@@ -0,0 +1,142 @@
+package marvel
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/gkatanacio/marvel-characters-api/internal/logging"
+)
+
+// RefreshConfig configures the background sync of the cache with Marvel's API.
+type RefreshConfig struct {
+	// Interval is the average time between syncs. Zero disables the background sync.
+	Interval time.Duration `envconfig:"INTERVAL" default:"5m"`
+	// Jitter is the fraction (0 to 1) by which each interval is randomly shortened or lengthened.
+	Jitter float64 `envconfig:"JITTER" default:"0.1"`
+	// ReconcileInterval is the minimum time between full reconciliations of the cache,
+	// which take the place of a regular sync when due. Zero disables reconciliation.
+	ReconcileInterval time.Duration `envconfig:"RECONCILE_INTERVAL" default:"24h"`
+	// GraphInterval is the minimum time between syncs of the co-appearance graph, which follow
+	// a regular sync when due. The first one (right after the first regular sync) builds the graph
+	// from every comic, which takes several hundred calls to Marvel's API. A failed sync is retried
+	// with a growing delay (up to the interval), resuming from where it stopped. Zero disables the graph.
+	GraphInterval time.Duration `envconfig:"GRAPH_INTERVAL" default:"0"`
+}
+
+// Refresher periodically syncs the cache with Marvel's API in the background,
+// so that requests can be answered from the cache alone.
+type Refresher struct {
+	service Servicer
+	cfg     RefreshConfig
+	rnd     *lockedRand
+	stop    chan struct{}
+	done    chan struct{}
+	logger  *logging.Logger
+}
+
+func NewRefresher(service Servicer, cfg RefreshConfig, logger *logging.Logger) *Refresher {
+	return &Refresher{
+		service: service,
+		cfg:     cfg,
+		rnd:     newLockedRand(),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+		logger:  logger,
+	}
+}
+
+// Start launches the background sync loop.
+func (r *Refresher) Start() {
+	go r.run()
+}
+
+// Stop cancels any sync in progress and waits for the background sync loop to exit.
+func (r *Refresher) Stop() {
+	close(r.stop)
+	<-r.done
+}
+
+func (r *Refresher) run() {
+	defer close(r.done)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go func() {
+		select {
+		case <-r.stop:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	lastReconcile := time.Now()
+	// nextGraphSync is when the co-appearance graph is next due for a sync (right away at first)
+	var nextGraphSync time.Time
+	var graphRetryDelay time.Duration
+
+	for {
+		select {
+		case <-time.After(r.nextInterval()):
+		case <-ctx.Done():
+			return
+		}
+
+		start := time.Now()
+
+		if r.cfg.ReconcileInterval > 0 && start.Sub(lastReconcile) >= r.cfg.ReconcileInterval {
+			result, err := r.service.ReconcileCache(ctx)
+			if err != nil {
+				r.logger.Error("background cache reconciliation failed", "error", err)
+				continue
+			}
+			lastReconcile = start
+			if !result.Skipped {
+				r.logger.Info("background cache reconciliation done", "duration", time.Since(start))
+				continue
+			}
+			// nothing was applied, so the characters modified since the last sync are still fetched
+		}
+
+		if err := r.service.SyncCache(ctx); err != nil {
+			r.logger.Error("background cache sync failed", "error", err)
+			continue
+		}
+		r.logger.Info("background cache sync done", "duration", time.Since(start))
+
+		if r.cfg.GraphInterval > 0 && !start.Before(nextGraphSync) {
+			graphStart := time.Now()
+			if err := r.service.SyncGraph(ctx); err != nil {
+				// back off, so a failing sync does not eat up the call budget
+				graphRetryDelay = r.graphRetryDelay(graphRetryDelay)
+				nextGraphSync = start.Add(graphRetryDelay)
+				r.logger.Error("background co-appearance graph sync failed", "retryIn", graphRetryDelay, "error", err)
+				continue
+			}
+			graphRetryDelay = 0
+			nextGraphSync = start.Add(r.cfg.GraphInterval)
+			r.logger.Info("background co-appearance graph sync done", "duration", time.Since(graphStart))
+		}
+	}
+}
+
+// graphRetryDelay returns the delay before retrying a failed sync of the co-appearance graph,
+// given the previous delay (zero after the first failure): twice the sync interval at first,
+// then doubling up to the graph interval.
+func (r *Refresher) graphRetryDelay(previous time.Duration) time.Duration {
+	delay := 2 * previous
+	if delay == 0 {
+		delay = 2 * r.cfg.Interval
+	}
+	if delay > r.cfg.GraphInterval {
+		delay = r.cfg.GraphInterval
+	}
+	return delay
+}
+
+func (r *Refresher) nextInterval() time.Duration {
+	jitter := math.Max(0, math.Min(1, r.cfg.Jitter))
+	factor := 1 + jitter*(2*r.rnd.Float64()-1)
+	return time.Duration(float64(r.cfg.Interval) * factor)
+}
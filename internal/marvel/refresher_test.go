@@ -0,0 +1,110 @@
+package marvel_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gkatanacio/marvel-characters-api/internal/errs"
+	"github.com/gkatanacio/marvel-characters-api/internal/logging"
+	"github.com/gkatanacio/marvel-characters-api/internal/marvel"
+	"github.com/gkatanacio/marvel-characters-api/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_Refresher_SyncsPeriodicallyUntilStopped(t *testing.T) {
+	// given
+	serviceMock := new(mocks.Servicer)
+	serviceMock.On("SyncCache", mock.Anything).Return(errs.NewBadGateway("error response from marvel api")).Once()
+	serviceMock.On("SyncCache", mock.Anything).Return(nil)
+
+	refresher := marvel.NewRefresher(serviceMock, marvel.RefreshConfig{
+		Interval: 10 * time.Millisecond,
+		Jitter:   0.5,
+	}, logging.Discard())
+
+	// when
+	refresher.Start()
+	time.Sleep(100 * time.Millisecond)
+	refresher.Stop()
+	calls := len(serviceMock.Calls)
+	time.Sleep(30 * time.Millisecond)
+
+	// then
+	assert.GreaterOrEqual(t, calls, 3)
+	assert.Equal(t, calls, len(serviceMock.Calls), "no syncs after being stopped")
+}
+
+func Test_Refresher_ReconcilesWhenDue(t *testing.T) {
+	// given
+	serviceMock := new(mocks.Servicer)
+	serviceMock.On("SyncCache", mock.Anything).Return(nil)
+	serviceMock.On("ReconcileCache", mock.Anything).Return(&marvel.ReconcileResult{}, nil)
+
+	refresher := marvel.NewRefresher(serviceMock, marvel.RefreshConfig{
+		Interval:          10 * time.Millisecond,
+		ReconcileInterval: 35 * time.Millisecond,
+	}, logging.Discard())
+
+	// when
+	refresher.Start()
+	time.Sleep(100 * time.Millisecond)
+	refresher.Stop()
+
+	// then
+	serviceMock.AssertCalled(t, "SyncCache", mock.Anything)
+	serviceMock.AssertCalled(t, "ReconcileCache", mock.Anything)
+}
+
+func Test_Refresher_SyncsWhenReconciliationSkipped(t *testing.T) {
+	// given
+	serviceMock := new(mocks.Servicer)
+	serviceMock.On("SyncCache", mock.Anything).Return(nil)
+	serviceMock.On("ReconcileCache", mock.Anything).Return(&marvel.ReconcileResult{Skipped: true}, nil)
+
+	refresher := marvel.NewRefresher(serviceMock, marvel.RefreshConfig{
+		Interval:          10 * time.Millisecond,
+		ReconcileInterval: time.Nanosecond,
+	}, logging.Discard())
+
+	// when
+	refresher.Start()
+	time.Sleep(35 * time.Millisecond)
+	refresher.Stop()
+
+	// then
+	serviceMock.AssertCalled(t, "ReconcileCache", mock.Anything)
+	serviceMock.AssertCalled(t, "SyncCache", mock.Anything)
+}
+
+func Test_Refresher_BacksOffFailedGraphSyncs(t *testing.T) {
+	// given
+	serviceMock := new(mocks.Servicer)
+	serviceMock.On("SyncCache", mock.Anything).Return(nil)
+	serviceMock.On("SyncGraph", mock.Anything).Return(errs.NewBadGateway("error response from marvel api"))
+
+	refresher := marvel.NewRefresher(serviceMock, marvel.RefreshConfig{
+		Interval:      10 * time.Millisecond,
+		GraphInterval: time.Hour,
+	}, logging.Discard())
+
+	// when
+	refresher.Start()
+	time.Sleep(100 * time.Millisecond)
+	refresher.Stop()
+
+	// then
+	syncs, graphSyncs := 0, 0
+	for _, call := range serviceMock.Calls {
+		switch call.Method {
+		case "SyncCache":
+			syncs++
+		case "SyncGraph":
+			graphSyncs++
+		}
+	}
+	assert.GreaterOrEqual(t, syncs, 3)
+	// retried after 20ms, then 40ms, rather than on every sync
+	assert.LessOrEqual(t, graphSyncs, 3)
+	assert.GreaterOrEqual(t, graphSyncs, 2)
+}
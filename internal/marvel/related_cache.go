@@ -0,0 +1,60 @@
+package marvel
+
+import (
+	"fmt"
+	"time"
+)
+
+// RelatedCacheConfig configures the caching of the resources related to characters
+// (e.g. the comics a character appears in).
+type RelatedCacheConfig struct {
+	// TTL is how long a page of related resources is cached. Zero disables caching.
+	TTL time.Duration `envconfig:"TTL" default:"1h"`
+	// MaxEntries is the maximum number of cached pages, after which the least
+	// recently used ones are evicted. Zero means no limit.
+	MaxEntries int `envconfig:"MAX_ENTRIES" default:"1000"`
+}
+
+// relatedPage is a page of resources related to a character, along with the total number of them.
+type relatedPage struct {
+	items interface{} // a slice of the type of resource requested
+	total int
+}
+
+// relatedCache is an in-memory (LRU) cache of the pages of resources related to characters.
+type relatedCache struct {
+	cfg   RelatedCacheConfig
+	pages *lruCache
+}
+
+func newRelatedCache(cfg RelatedCacheConfig) *relatedCache {
+	return &relatedCache{
+		cfg:   cfg,
+		pages: newLruCache(cfg.MaxEntries),
+	}
+}
+
+// relatedCacheKey identifies a page of `resource` (e.g. "comics") related to the character with the given ID.
+func relatedCacheKey(resource string, charId int, query ResourceQuery) string {
+	return fmt.Sprintf("%s:%d:%d:%d:%s:%s:%s", resource, charId, query.Limit, query.Offset, query.OrderBy, query.Format, query.DateRange)
+}
+
+func (c *relatedCache) get(key string) (*relatedPage, bool) {
+	page, ok := c.pages.get(key)
+	if !ok {
+		return nil, false
+	}
+	return page.(*relatedPage), true
+}
+
+func (c *relatedCache) set(key string, page *relatedPage) {
+	if c.cfg.TTL <= 0 {
+		return
+	}
+	c.pages.set(key, page, c.cfg.TTL)
+}
+
+// len returns the number of cached pages (including expired ones not yet evicted).
+func (c *relatedCache) len() int {
+	return c.pages.len()
+}
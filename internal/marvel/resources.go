@@ -0,0 +1,366 @@
+package marvel
+
+import (
+	"time"
+)
+
+const (
+	// dateFormatMarvelApiEvent is the format of the start and end dates of events
+	dateFormatMarvelApiEvent = "2006-01-02 15:04:05"
+)
+
+// ResourceQuery holds the pagination and filtering criteria for listing resources (e.g. comics).
+// The filters are passed through to Marvel's API as is, and are left empty if not used.
+type ResourceQuery struct {
+	Limit  int
+	Offset int
+	// OrderBy is the field to sort by, prefixed with "-" for descending order
+	OrderBy string
+	// Format is the format of the comics (e.g. "comic" or "trade paperback")
+	Format string
+	// DateRange is the range of dates (YYYY-MM-DD,YYYY-MM-DD) the comics were released in
+	DateRange string
+}
+
+type MarvelApiComicData struct {
+	Id                 int              `json:"id"`
+	DigitalId          int              `json:"digitalId"`
+	Title              string           `json:"title"`
+	IssueNumber        float64          `json:"issueNumber"`
+	VariantDescription string           `json:"variantDescription"`
+	Description        string           `json:"description"`
+	Modified           string           `json:"modified"`
+	Isbn               string           `json:"isbn"`
+	Upc                string           `json:"upc"`
+	Format             string           `json:"format"`
+	PageCount          int              `json:"pageCount"`
+	ResourceURI        string           `json:"resourceURI"`
+	Urls               []*Link          `json:"urls"`
+	Series             *ResourceSummary `json:"series"`
+	Dates              []*MarvelApiDate `json:"dates"`
+	Prices             []*Price         `json:"prices"`
+	Thumbnail          *MarvelApiImage  `json:"thumbnail"`
+	Creators           *ResourceList    `json:"creators"`
+	Characters         *ResourceList    `json:"characters"`
+	Stories            *ResourceList    `json:"stories"`
+	Events             *ResourceList    `json:"events"`
+}
+
+type MarvelApiDate struct {
+	Type string `json:"type"`
+	Date string `json:"date"`
+}
+
+type MarvelApiSeriesData struct {
+	Id          int              `json:"id"`
+	Title       string           `json:"title"`
+	Description string           `json:"description"`
+	ResourceURI string           `json:"resourceURI"`
+	Urls        []*Link          `json:"urls"`
+	StartYear   int              `json:"startYear"`
+	EndYear     int              `json:"endYear"`
+	Rating      string           `json:"rating"`
+	Type        string           `json:"type"`
+	Modified    string           `json:"modified"`
+	Thumbnail   *MarvelApiImage  `json:"thumbnail"`
+	Creators    *ResourceList    `json:"creators"`
+	Characters  *ResourceList    `json:"characters"`
+	Stories     *ResourceList    `json:"stories"`
+	Comics      *ResourceList    `json:"comics"`
+	Events      *ResourceList    `json:"events"`
+	Next        *ResourceSummary `json:"next"`
+	Previous    *ResourceSummary `json:"previous"`
+}
+
+type MarvelApiEventData struct {
+	Id          int              `json:"id"`
+	Title       string           `json:"title"`
+	Description string           `json:"description"`
+	ResourceURI string           `json:"resourceURI"`
+	Urls        []*Link          `json:"urls"`
+	Modified    string           `json:"modified"`
+	Start       string           `json:"start"`
+	End         string           `json:"end"`
+	Thumbnail   *MarvelApiImage  `json:"thumbnail"`
+	Creators    *ResourceList    `json:"creators"`
+	Characters  *ResourceList    `json:"characters"`
+	Stories     *ResourceList    `json:"stories"`
+	Comics      *ResourceList    `json:"comics"`
+	Series      *ResourceList    `json:"series"`
+	Next        *ResourceSummary `json:"next"`
+	Previous    *ResourceSummary `json:"previous"`
+}
+
+type MarvelApiStoryData struct {
+	Id            int              `json:"id"`
+	Title         string           `json:"title"`
+	Description   string           `json:"description"`
+	ResourceURI   string           `json:"resourceURI"`
+	Type          string           `json:"type"`
+	Modified      string           `json:"modified"`
+	Thumbnail     *MarvelApiImage  `json:"thumbnail"`
+	Creators      *ResourceList    `json:"creators"`
+	Characters    *ResourceList    `json:"characters"`
+	Series        *ResourceList    `json:"series"`
+	Comics        *ResourceList    `json:"comics"`
+	Events        *ResourceList    `json:"events"`
+	OriginalIssue *ResourceSummary `json:"originalIssue"`
+}
+
+type MarvelApiCreatorData struct {
+	Id          int             `json:"id"`
+	FirstName   string          `json:"firstName"`
+	MiddleName  string          `json:"middleName"`
+	LastName    string          `json:"lastName"`
+	Suffix      string          `json:"suffix"`
+	FullName    string          `json:"fullName"`
+	Modified    string          `json:"modified"`
+	ResourceURI string          `json:"resourceURI"`
+	Urls        []*Link         `json:"urls"`
+	Thumbnail   *MarvelApiImage `json:"thumbnail"`
+	Series      *ResourceList   `json:"series"`
+	Stories     *ResourceList   `json:"stories"`
+	Comics      *ResourceList   `json:"comics"`
+	Events      *ResourceList   `json:"events"`
+}
+
+type Comic struct {
+	Id                 int              `json:"id"`
+	DigitalId          int              `json:"digitalId,omitempty"`
+	Title              string           `json:"title"`
+	IssueNumber        float64          `json:"issueNumber"`
+	VariantDescription string           `json:"variantDescription,omitempty"`
+	Description        string           `json:"description"`
+	Modified           *time.Time       `json:"modified,omitempty"`
+	Isbn               string           `json:"isbn,omitempty"`
+	Upc                string           `json:"upc,omitempty"`
+	Format             string           `json:"format,omitempty"`
+	PageCount          int              `json:"pageCount"`
+	ResourceURI        string           `json:"resourceURI,omitempty"`
+	Urls               []*Link          `json:"urls,omitempty"`
+	Series             *ResourceSummary `json:"series,omitempty"`
+	Dates              []*ComicDate     `json:"dates,omitempty"`
+	Prices             []*Price         `json:"prices,omitempty"`
+	Thumbnail          *Thumbnail       `json:"thumbnail,omitempty"`
+	Creators           *ResourceList    `json:"creators,omitempty"`
+	Characters         *ResourceList    `json:"characters,omitempty"`
+	Stories            *ResourceList    `json:"stories,omitempty"`
+	Events             *ResourceList    `json:"events,omitempty"`
+}
+
+// ComicDate is a key date of a comic (e.g. its "onsaleDate" or "focDate").
+type ComicDate struct {
+	Type string    `json:"type"`
+	Date time.Time `json:"date"`
+}
+
+// Price is a price of a comic (e.g. its "printPrice" or "digitalPurchasePrice").
+type Price struct {
+	Type  string  `json:"type"`
+	Price float64 `json:"price"`
+}
+
+type Series struct {
+	Id          int              `json:"id"`
+	Title       string           `json:"title"`
+	Description string           `json:"description"`
+	ResourceURI string           `json:"resourceURI,omitempty"`
+	Urls        []*Link          `json:"urls,omitempty"`
+	StartYear   int              `json:"startYear"`
+	EndYear     int              `json:"endYear"`
+	Rating      string           `json:"rating,omitempty"`
+	Type        string           `json:"type,omitempty"`
+	Modified    *time.Time       `json:"modified,omitempty"`
+	Thumbnail   *Thumbnail       `json:"thumbnail,omitempty"`
+	Creators    *ResourceList    `json:"creators,omitempty"`
+	Characters  *ResourceList    `json:"characters,omitempty"`
+	Stories     *ResourceList    `json:"stories,omitempty"`
+	Comics      *ResourceList    `json:"comics,omitempty"`
+	Events      *ResourceList    `json:"events,omitempty"`
+	Next        *ResourceSummary `json:"next,omitempty"`
+	Previous    *ResourceSummary `json:"previous,omitempty"`
+}
+
+type Event struct {
+	Id          int              `json:"id"`
+	Title       string           `json:"title"`
+	Description string           `json:"description"`
+	ResourceURI string           `json:"resourceURI,omitempty"`
+	Urls        []*Link          `json:"urls,omitempty"`
+	Modified    *time.Time       `json:"modified,omitempty"`
+	Start       *time.Time       `json:"start,omitempty"`
+	End         *time.Time       `json:"end,omitempty"`
+	Thumbnail   *Thumbnail       `json:"thumbnail,omitempty"`
+	Creators    *ResourceList    `json:"creators,omitempty"`
+	Characters  *ResourceList    `json:"characters,omitempty"`
+	Stories     *ResourceList    `json:"stories,omitempty"`
+	Comics      *ResourceList    `json:"comics,omitempty"`
+	Series      *ResourceList    `json:"series,omitempty"`
+	Next        *ResourceSummary `json:"next,omitempty"`
+	Previous    *ResourceSummary `json:"previous,omitempty"`
+}
+
+type Story struct {
+	Id            int              `json:"id"`
+	Title         string           `json:"title"`
+	Description   string           `json:"description"`
+	ResourceURI   string           `json:"resourceURI,omitempty"`
+	Type          string           `json:"type,omitempty"`
+	Modified      *time.Time       `json:"modified,omitempty"`
+	Thumbnail     *Thumbnail       `json:"thumbnail,omitempty"`
+	Creators      *ResourceList    `json:"creators,omitempty"`
+	Characters    *ResourceList    `json:"characters,omitempty"`
+	Series        *ResourceList    `json:"series,omitempty"`
+	Comics        *ResourceList    `json:"comics,omitempty"`
+	Events        *ResourceList    `json:"events,omitempty"`
+	OriginalIssue *ResourceSummary `json:"originalIssue,omitempty"`
+}
+
+type Creator struct {
+	Id          int           `json:"id"`
+	FirstName   string        `json:"firstName,omitempty"`
+	MiddleName  string        `json:"middleName,omitempty"`
+	LastName    string        `json:"lastName,omitempty"`
+	Suffix      string        `json:"suffix,omitempty"`
+	FullName    string        `json:"fullName"`
+	Modified    *time.Time    `json:"modified,omitempty"`
+	ResourceURI string        `json:"resourceURI,omitempty"`
+	Urls        []*Link       `json:"urls,omitempty"`
+	Thumbnail   *Thumbnail    `json:"thumbnail,omitempty"`
+	Series      *ResourceList `json:"series,omitempty"`
+	Stories     *ResourceList `json:"stories,omitempty"`
+	Comics      *ResourceList `json:"comics,omitempty"`
+	Events      *ResourceList `json:"events,omitempty"`
+}
+
+func (d *MarvelApiComicData) toComic() *Comic {
+	comic := &Comic{
+		Id:                 d.Id,
+		DigitalId:          d.DigitalId,
+		Title:              d.Title,
+		IssueNumber:        d.IssueNumber,
+		VariantDescription: d.VariantDescription,
+		Description:        d.Description,
+		Modified:           parseMarvelApiTime(dateFormatMarvelApi, d.Modified),
+		Isbn:               d.Isbn,
+		Upc:                d.Upc,
+		Format:             d.Format,
+		PageCount:          d.PageCount,
+		ResourceURI:        d.ResourceURI,
+		Urls:               d.Urls,
+		Series:             d.Series,
+		Prices:             d.Prices,
+		Thumbnail:          d.Thumbnail.toThumbnail(),
+		Creators:           d.Creators,
+		Characters:         d.Characters,
+		Stories:            d.Stories,
+		Events:             d.Events,
+	}
+
+	for _, date := range d.Dates {
+		if t := parseMarvelApiTime(dateFormatMarvelApi, date.Date); t != nil {
+			comic.Dates = append(comic.Dates, &ComicDate{Type: date.Type, Date: *t})
+		}
+	}
+
+	return comic
+}
+
+func (d *MarvelApiSeriesData) toSeries() *Series {
+	return &Series{
+		Id:          d.Id,
+		Title:       d.Title,
+		Description: d.Description,
+		ResourceURI: d.ResourceURI,
+		Urls:        d.Urls,
+		StartYear:   d.StartYear,
+		EndYear:     d.EndYear,
+		Rating:      d.Rating,
+		Type:        d.Type,
+		Modified:    parseMarvelApiTime(dateFormatMarvelApi, d.Modified),
+		Thumbnail:   d.Thumbnail.toThumbnail(),
+		Creators:    d.Creators,
+		Characters:  d.Characters,
+		Stories:     d.Stories,
+		Comics:      d.Comics,
+		Events:      d.Events,
+		Next:        d.Next,
+		Previous:    d.Previous,
+	}
+}
+
+func (d *MarvelApiEventData) toEvent() *Event {
+	return &Event{
+		Id:          d.Id,
+		Title:       d.Title,
+		Description: d.Description,
+		ResourceURI: d.ResourceURI,
+		Urls:        d.Urls,
+		Modified:    parseMarvelApiTime(dateFormatMarvelApi, d.Modified),
+		Start:       parseMarvelApiTime(dateFormatMarvelApiEvent, d.Start),
+		End:         parseMarvelApiTime(dateFormatMarvelApiEvent, d.End),
+		Thumbnail:   d.Thumbnail.toThumbnail(),
+		Creators:    d.Creators,
+		Characters:  d.Characters,
+		Stories:     d.Stories,
+		Comics:      d.Comics,
+		Series:      d.Series,
+		Next:        d.Next,
+		Previous:    d.Previous,
+	}
+}
+
+func (d *MarvelApiStoryData) toStory() *Story {
+	return &Story{
+		Id:            d.Id,
+		Title:         d.Title,
+		Description:   d.Description,
+		ResourceURI:   d.ResourceURI,
+		Type:          d.Type,
+		Modified:      parseMarvelApiTime(dateFormatMarvelApi, d.Modified),
+		Thumbnail:     d.Thumbnail.toThumbnail(),
+		Creators:      d.Creators,
+		Characters:    d.Characters,
+		Series:        d.Series,
+		Comics:        d.Comics,
+		Events:        d.Events,
+		OriginalIssue: d.OriginalIssue,
+	}
+}
+
+func (d *MarvelApiCreatorData) toCreator() *Creator {
+	return &Creator{
+		Id:          d.Id,
+		FirstName:   d.FirstName,
+		MiddleName:  d.MiddleName,
+		LastName:    d.LastName,
+		Suffix:      d.Suffix,
+		FullName:    d.FullName,
+		Modified:    parseMarvelApiTime(dateFormatMarvelApi, d.Modified),
+		ResourceURI: d.ResourceURI,
+		Urls:        d.Urls,
+		Thumbnail:   d.Thumbnail.toThumbnail(),
+		Series:      d.Series,
+		Stories:     d.Stories,
+		Comics:      d.Comics,
+		Events:      d.Events,
+	}
+}
+
+// toThumbnail returns nil if the image is missing.
+func (i *MarvelApiImage) toThumbnail() *Thumbnail {
+	if i == nil || i.Path == "" {
+		return nil
+	}
+	return newThumbnail(i.Path, i.Extension)
+}
+
+// parseMarvelApiTime returns nil if `s` is not a valid date. Marvel's API uses an invalid
+// date (e.g. "-0001-11-30T00:00:00-0500") for resources that were never modified.
+func parseMarvelApiTime(layout, s string) *time.Time {
+	t, err := time.Parse(layout, s)
+	if err != nil || t.Year() <= 0 {
+		return nil
+	}
+	return &t
+}
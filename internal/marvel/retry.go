@@ -0,0 +1,124 @@
+package marvel
+
+import (
+	"errors"
+	"io"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// RetryPolicy controls how failed calls to Marvel's API are retried.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts per call (including the first one).
+	// Values below 1 are treated as 1, i.e. no retries.
+	MaxAttempts int `envconfig:"MAX_ATTEMPTS" default:"3"`
+	// BaseDelay is the delay before the first retry. It is doubled on every subsequent retry.
+	BaseDelay time.Duration `envconfig:"BASE_DELAY" default:"250ms"`
+	// MaxDelay caps the computed delay between attempts, as well as any Retry-After
+	// requested by Marvel's API (calls asking for a longer wait are not retried).
+	MaxDelay time.Duration `envconfig:"MAX_DELAY" default:"5s"`
+	// Jitter is the fraction (0 to 1) of each delay that is randomized.
+	Jitter float64 `envconfig:"JITTER" default:"0.5"`
+	// RetryableStatuses are the HTTP status codes from Marvel's API that are retried.
+	// Client errors (4xx) other than 429 (Too Many Requests) are never retried.
+	RetryableStatuses []int `envconfig:"STATUSES" default:"429,500,502,503,504"`
+	// RetryOnTimeout enables retrying calls that timed out.
+	RetryOnTimeout bool `envconfig:"ON_TIMEOUT" default:"true"`
+	// RetryOnConnectionError enables retrying calls that failed due to refused,
+	// reset or prematurely closed connections.
+	RetryOnConnectionError bool `envconfig:"ON_CONNECTION_ERROR" default:"true"`
+}
+
+func (p *RetryPolicy) maxAttempts() int {
+	if p.MaxAttempts < 1 {
+		return 1
+	}
+	return p.MaxAttempts
+}
+
+func (p *RetryPolicy) isRetryableStatus(status int) bool {
+	if status < http.StatusInternalServerError && status != http.StatusTooManyRequests {
+		return false
+	}
+
+	for _, s := range p.RetryableStatuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *RetryPolicy) isRetryableErr(err error) bool {
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return p.RetryOnTimeout
+	}
+
+	if errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF) {
+		return p.RetryOnConnectionError
+	}
+
+	return false
+}
+
+// backoff computes the delay before the retry following the given (1-based) attempt.
+func (p *RetryPolicy) backoff(attempt int, rnd *lockedRand) time.Duration {
+	delay := float64(p.BaseDelay) * math.Pow(2, float64(attempt-1))
+	if p.MaxDelay > 0 && delay > float64(p.MaxDelay) {
+		delay = float64(p.MaxDelay)
+	}
+
+	jitter := math.Max(0, math.Min(1, p.Jitter))
+	delay -= delay * jitter * rnd.Float64()
+
+	return time.Duration(delay)
+}
+
+// parseRetryAfter reads the Retry-After header, which can either be
+// a number of seconds or an HTTP date.
+func parseRetryAfter(h http.Header) (time.Duration, bool) {
+	v := h.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+
+	if secs, err := strconv.Atoi(v); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second, true
+	}
+
+	if t, err := http.ParseTime(v); err == nil {
+		d := time.Until(t)
+		if d < 0 {
+			d = 0
+		}
+		return d, true
+	}
+
+	return 0, false
+}
+
+// lockedRand is a source of random numbers safe for concurrent use.
+type lockedRand struct {
+	sync.Mutex
+	r *rand.Rand
+}
+
+func newLockedRand() *lockedRand {
+	return &lockedRand{r: rand.New(rand.NewSource(time.Now().UnixNano()))}
+}
+
+func (lr *lockedRand) Float64() float64 {
+	lr.Lock()
+	defer lr.Unlock()
+	return lr.r.Float64()
+}
@@ -0,0 +1,260 @@
+package marvel
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+const (
+	nameTokenWeight        = 3.0
+	descriptionTokenWeight = 1.0
+	// compactNameWeight rewards queries matching a whole name regardless of
+	// spaces and punctuation (e.g. "spiderman" for "Spider-Man")
+	compactNameWeight = 5.0
+)
+
+// stopWords are common words skipped when indexing descriptions.
+var stopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true, "be": true, "by": true,
+	"for": true, "from": true, "has": true, "he": true, "her": true, "his": true, "in": true, "is": true,
+	"it": true, "its": true, "of": true, "on": true, "she": true, "that": true, "the": true, "their": true,
+	"they": true, "to": true, "was": true, "were": true, "with": true,
+}
+
+// SearchIndex is an in-memory inverted index over character names and descriptions,
+// supporting ranked, typo-tolerant searches.
+type SearchIndex struct {
+	sync.RWMutex
+	chars map[int]*Character
+	// postings maps each token to the IDs of the characters containing it, with the token's weight
+	postings map[string]map[int]float64
+	// compactNames maps the IDs of the characters to their names stripped of non-alphanumerics
+	compactNames map[int]string
+}
+
+func NewSearchIndex() *SearchIndex {
+	return &SearchIndex{
+		chars:        make(map[int]*Character),
+		postings:     make(map[string]map[int]float64),
+		compactNames: make(map[int]string),
+	}
+}
+
+// Replace rebuilds the index from scratch with `chars`.
+func (ix *SearchIndex) Replace(chars []*Character) {
+	ix.Lock()
+	defer ix.Unlock()
+
+	ix.chars = make(map[int]*Character, len(chars))
+	ix.postings = make(map[string]map[int]float64)
+	ix.compactNames = make(map[int]string, len(chars))
+	for _, c := range chars {
+		ix.add(c)
+	}
+}
+
+// Upsert adds `chars` to the index, replacing those with the same IDs.
+func (ix *SearchIndex) Upsert(chars []*Character) {
+	ix.Lock()
+	defer ix.Unlock()
+
+	for _, c := range chars {
+		ix.remove(c.Id)
+		ix.add(c)
+	}
+}
+
+// Len returns the number of indexed characters.
+func (ix *SearchIndex) Len() int {
+	ix.RLock()
+	defer ix.RUnlock()
+	return len(ix.chars)
+}
+
+// Search returns the characters matching `query`, from the most to the least relevant. Query
+// words are matched against the words of the names (tolerating typos) and descriptions.
+func (ix *SearchIndex) Search(query string) []*SearchResult {
+	queryTokens := tokenize(query)
+	compactQuery := compact(query)
+
+	ix.RLock()
+	defer ix.RUnlock()
+
+	scores := make(map[int]float64)
+
+	for _, qt := range queryTokens {
+		for token, ids := range ix.postings {
+			similarity := tokenSimilarity(qt, token)
+			if similarity == 0 {
+				continue
+			}
+			for id, weight := range ids {
+				// typos are only tolerated in names; descriptions need exact words
+				if weight < nameTokenWeight && similarity < 1 {
+					continue
+				}
+				scores[id] += weight * similarity
+			}
+		}
+	}
+
+	if len(compactQuery) >= 3 {
+		for id, name := range ix.compactNames {
+			if similarity := tokenSimilarity(compactQuery, name); similarity > 0 {
+				scores[id] += compactNameWeight * similarity
+			}
+		}
+	}
+
+	results := make([]*SearchResult, 0, len(scores))
+	for id, score := range scores {
+		charCopy := *ix.chars[id]
+		results = append(results, &SearchResult{Character: &charCopy, Score: score})
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].Name < results[j].Name
+	})
+
+	return results
+}
+
+func (ix *SearchIndex) add(c *Character) {
+	ix.chars[c.Id] = c
+	ix.compactNames[c.Id] = compact(c.Name)
+
+	for _, t := range tokenize(c.Name) {
+		ix.addPosting(t, c.Id, nameTokenWeight)
+	}
+	for _, t := range tokenize(c.Description) {
+		if !stopWords[t] {
+			ix.addPosting(t, c.Id, descriptionTokenWeight)
+		}
+	}
+}
+
+// addPosting records that the character contains the token, keeping
+// the highest weight if the token appears in several fields.
+func (ix *SearchIndex) addPosting(token string, id int, weight float64) {
+	ids, exists := ix.postings[token]
+	if !exists {
+		ids = make(map[int]float64)
+		ix.postings[token] = ids
+	}
+	if weight > ids[id] {
+		ids[id] = weight
+	}
+}
+
+func (ix *SearchIndex) remove(id int) {
+	c, exists := ix.chars[id]
+	if !exists {
+		return
+	}
+
+	for _, t := range append(tokenize(c.Name), tokenize(c.Description)...) {
+		if ids, exists := ix.postings[t]; exists {
+			delete(ids, id)
+			if len(ids) == 0 {
+				delete(ix.postings, t)
+			}
+		}
+	}
+	delete(ix.chars, id)
+	delete(ix.compactNames, id)
+}
+
+// tokenize splits `s` into lowercase alphanumeric words.
+func tokenize(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
+
+// compact lowercases `s` and strips it of anything but letters and numbers.
+func compact(s string) string {
+	return strings.Join(tokenize(s), "")
+}
+
+// tokenSimilarity scores how well query token `q` matches indexed token `t`: 1 for an exact
+// match, less for a prefix match (of at least 3 characters) or a match within a small edit
+// distance (1 edit for tokens of 4+ characters, 2 edits for tokens of 8+ characters), 0 otherwise.
+func tokenSimilarity(q, t string) float64 {
+	if q == t {
+		return 1
+	}
+
+	if len(q) >= 3 && strings.HasPrefix(t, q) {
+		return 0.5 + 0.4*float64(len(q))/float64(len(t))
+	}
+
+	maxEdits := 0
+	if len(q) >= 8 {
+		maxEdits = 2
+	} else if len(q) >= 4 {
+		maxEdits = 1
+	}
+	if maxEdits == 0 || abs(len(q)-len(t)) > maxEdits {
+		return 0
+	}
+
+	if d := editDistance(q, t, maxEdits); d <= maxEdits {
+		return 0.8 - 0.2*float64(d-1)
+	}
+	return 0
+}
+
+// editDistance computes the Levenshtein distance between `a` and `b`,
+// giving up (returning max+1) as soon as it exceeds `max`.
+func editDistance(a, b string, max int) int {
+	ra, rb := []rune(a), []rune(b)
+
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		rowMin := curr[0]
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min3(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			if curr[j] < rowMin {
+				rowMin = curr[j]
+			}
+		}
+		if rowMin > max {
+			return max + 1
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(rb)]
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
+
+func abs(i int) int {
+	if i < 0 {
+		return -i
+	}
+	return i
+}
@@ -0,0 +1,98 @@
+package marvel_test
+
+import (
+	"testing"
+
+	"github.com/gkatanacio/marvel-characters-api/internal/marvel"
+	"github.com/stretchr/testify/assert"
+)
+
+func testSearchIndex() *marvel.SearchIndex {
+	index := marvel.NewSearchIndex()
+	index.Replace([]*marvel.Character{
+		{Id: 1009610, Name: "Spider-Man", Description: "Bitten by a radioactive spider, high school student Peter Parker gained the speed, strength and powers of a spider."},
+		{Id: 1009609, Name: "Spider-Girl (May Parker)", Description: "The daughter of Peter Parker."},
+		{Id: 1009718, Name: "Wolverine", Description: "Born with super-human senses and the power to heal from almost any wound."},
+		{Id: 1009351, Name: "Hulk", Description: "Caught in a gamma bomb explosion, Bruce Banner became the Hulk, who can smash anything."},
+		{Id: 1009165, Name: "Avengers", Description: "Earth's Mightiest Heroes, including the Hulk."},
+	})
+	return index
+}
+
+func resultNames(results []*marvel.SearchResult) []string {
+	names := make([]string, 0, len(results))
+	for _, r := range results {
+		names = append(names, r.Name)
+	}
+	return names
+}
+
+func Test_SearchIndex_Search(t *testing.T) {
+	testCases := map[string]struct {
+		query    string
+		expected string
+	}{
+		"exact name":            {"Wolverine", "Wolverine"},
+		"missing hyphen":        {"spiderman", "Spider-Man"},
+		"typo":                  {"wolverene", "Wolverine"},
+		"name and description":  {"hulk smash", "Hulk"},
+		"description only":      {"gamma", "Hulk"},
+		"name prefix":           {"wolv", "Wolverine"},
+		"spaces instead of -":   {"spider man", "Spider-Man"},
+		"several typos in name": {"spidermann", "Spider-Man"},
+	}
+
+	index := testSearchIndex()
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			// when
+			results := index.Search(tc.query)
+
+			// then
+			if assert.NotEmpty(t, results) {
+				assert.Equal(t, tc.expected, results[0].Name)
+			}
+		})
+	}
+}
+
+func Test_SearchIndex_Search_RankedByScore(t *testing.T) {
+	// given
+	index := testSearchIndex()
+
+	// when
+	results := index.Search("hulk")
+
+	// then
+	assert.Equal(t, []string{"Hulk", "Avengers"}, resultNames(results))
+	assert.Greater(t, results[0].Score, results[1].Score)
+}
+
+func Test_SearchIndex_Search_NoMatch(t *testing.T) {
+	// given
+	index := testSearchIndex()
+
+	// when
+	results := index.Search("xyzzy")
+
+	// then
+	assert.Empty(t, results)
+}
+
+func Test_SearchIndex_Upsert(t *testing.T) {
+	// given
+	index := testSearchIndex()
+
+	// when
+	index.Upsert([]*marvel.Character{
+		{Id: 1009351, Name: "Hulk", Description: "Green and angry."},
+		{Id: 1009368, Name: "Iron Man", Description: "Genius billionaire Tony Stark."},
+	})
+
+	// then
+	assert.Equal(t, 6, index.Len())
+	assert.Equal(t, []string{"Iron Man"}, resultNames(index.Search("ironman")))
+	assert.Equal(t, []string{"Hulk"}, resultNames(index.Search("angry")))
+	assert.Empty(t, index.Search("gamma"))
+}
@@ -1,33 +1,246 @@
 package marvel
 
 import (
+	"context"
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
+
+	"github.com/gkatanacio/marvel-characters-api/internal/errs"
+	"github.com/gkatanacio/marvel-characters-api/internal/logging"
 )
 
 // Servicer is the interface for the service layer containing functionality
 // for fetching and storing Marvel characters.
 type Servicer interface {
-	GetAllCharacterIds() ([]int, error)
-	GetCharacter(id int) (*Character, error)
-	ReloadCache() error
+	GetAllCharacterIds(ctx context.Context) ([]int, error)
+	GetAllCharacterIdsOrdered(ctx context.Context, orderBy string) ([]int, error)
+	GetCharacter(ctx context.Context, id int) (*Character, error)
+	GetCharacters(ctx context.Context, ids []int) (*CharacterBatch, error)
+	GetChanges(ctx context.Context, query ChangeQuery) (*ChangePage, error)
+	SubscribeChanges(lastEventId string) (*ChangeSubscription, error)
+	ReloadCache(ctx context.Context) error
+	SyncCache(ctx context.Context) error
+	ReconcileCache(ctx context.Context) (*ReconcileResult, error)
+	SyncStatus() SyncStatus
+	SearchCharacters(ctx context.Context, search CharacterSearch) (*CharacterPage, error)
+	Search(ctx context.Context, query string, limit, offset int) (*SearchPage, error)
+	GetComics(ctx context.Context, query ResourceQuery) ([]*Comic, int, error)
+	GetComic(ctx context.Context, id int) (*Comic, error)
+	GetSeriesList(ctx context.Context, query ResourceQuery) ([]*Series, int, error)
+	GetSeries(ctx context.Context, id int) (*Series, error)
+	GetEvents(ctx context.Context, query ResourceQuery) ([]*Event, int, error)
+	GetEvent(ctx context.Context, id int) (*Event, error)
+	GetStories(ctx context.Context, query ResourceQuery) ([]*Story, int, error)
+	GetStory(ctx context.Context, id int) (*Story, error)
+	GetCreators(ctx context.Context, query ResourceQuery) ([]*Creator, int, error)
+	GetCreator(ctx context.Context, id int) (*Creator, error)
+	GetCharacterComics(ctx context.Context, id int, query ResourceQuery) ([]*Comic, int, error)
+	GetCharacterSeries(ctx context.Context, id int, query ResourceQuery) ([]*Series, int, error)
+	GetCharacterEvents(ctx context.Context, id int, query ResourceQuery) ([]*Event, int, error)
+	GetCharacterStories(ctx context.Context, id int, query ResourceQuery) ([]*Story, int, error)
+	SyncGraph(ctx context.Context) error
+	GetConnections(ctx context.Context, id int, query ResourceQuery) ([]*Connection, int, error)
+	GetPath(ctx context.Context, from, to int) ([]*Connection, error)
+}
+
+// SyncStatus describes the outcome of the syncs of the cache with Marvel's API.
+type SyncStatus struct {
+	LastAttempt         time.Time        `json:"lastAttempt"`
+	LastSuccess         time.Time        `json:"lastSuccess"`
+	LastError           string           `json:"lastError,omitempty"`
+	FailedSyncs         int              `json:"failedSyncs"`
+	ConsecutiveFailures int              `json:"consecutiveFailures"`
+	LastReconcile       *ReconcileResult `json:"lastReconcile,omitempty"`
+}
+
+// ReconcileResult describes the differences found between the cached character IDs
+// and the complete list of characters from Marvel's API.
+type ReconcileResult struct {
+	Time    time.Time `json:"time"`
+	Added   int       `json:"added"`
+	Removed int       `json:"removed"`
+	// Skipped is true if the cache was left untouched because the complete list
+	// looked suspiciously short (e.g. due to a partial failure of Marvel's API).
+	Skipped bool `json:"skipped"`
+}
+
+// Stale reports whether the last sync attempt failed, i.e. the cache may be out of date.
+func (ss SyncStatus) Stale() bool {
+	return ss.LastAttempt.After(ss.LastSuccess)
 }
 
 // Service is the concrete implementation of Servicer. It uses a cache to
 // reduce calls to Marvel's API.
 type Service struct {
-	client MarvelDataFetcher
-	cache  CharacterCache
+	cfg         *Config
+	client      MarvelDataFetcher
+	cache       CharacterCache
+	detailCache CharacterDetailCache
+	related     *relatedCache
+	names       *NameIndex
+	search      *SearchIndex
+	graph       *CoAppearanceGraph
+	// graphCrawl is the sync of the co-appearance graph left unfinished by a failure (nil if none)
+	graphCrawl *graphCrawl
+	changes    *ChangeLog
+	calls      *callGroup
+	snapshots  *snapshotStore // nil if snapshots are disabled
+	logger     *logging.Logger
+
+	// cacheMu serializes the operations updating the cached character IDs
+	cacheMu sync.Mutex
+
+	syncStatusMu sync.RWMutex
+	syncStatus   SyncStatus
 }
 
-func NewService(client MarvelDataFetcher, cache CharacterCache) *Service {
+func NewService(cfg *Config, client MarvelDataFetcher, cache CharacterCache, detailCache CharacterDetailCache, logger *logging.Logger) *Service {
+	var snapshots *snapshotStore
+	if cfg.SnapshotPath != "" {
+		snapshots = newSnapshotStore(cfg.SnapshotPath)
+	}
+
 	return &Service{
-		client: client,
-		cache:  cache,
+		cfg:         cfg,
+		client:      client,
+		cache:       cache,
+		detailCache: detailCache,
+		related:     newRelatedCache(cfg.RelatedCache),
+		names:       NewNameIndex(),
+		search:      NewSearchIndex(),
+		graph:       NewCoAppearanceGraph(),
+		changes:     NewChangeLog(cfg.ChangeLogSize),
+		calls:       newCallGroup(),
+		snapshots:   snapshots,
+		logger:      logger,
+	}
+}
+
+// GetAllCharacterIds returns the character IDs of all Marvel characters, as of the last
+// successful sync. The cache is only synced on demand if it has never been synced before.
+// If syncing fails, the cached IDs are still served until they exceed the maximum staleness.
+// The IDs are sorted in ascending order.
+func (s *Service) GetAllCharacterIds(ctx context.Context) ([]int, error) {
+	if err := s.ensureSynced(ctx); err != nil {
+		return nil, err
+	}
+
+	cachedCharIds, _ := s.cache.GetCharacterIds()
+	charIds := cachedCharIds.ToSlice()
+	sort.Ints(charIds)
+	return charIds, nil
+}
+
+// GetAllCharacterIdsOrdered returns the character IDs of all Marvel characters like GetAllCharacterIds,
+// but sorted by `orderBy` ("name" or "modified", prefixed with "-" for descending order) using the
+// name index. Characters with the same name or modification time are sorted by ID.
+func (s *Service) GetAllCharacterIdsOrdered(ctx context.Context, orderBy string) ([]int, error) {
+	field := strings.TrimPrefix(orderBy, "-")
+	if field != "name" && field != "modified" {
+		return nil, errs.NewBadRequest("characters can only be ordered by name or modified")
+	}
+
+	if err := s.ensureSynced(ctx); err != nil {
+		return nil, err
 	}
+
+	if !s.names.Complete() {
+		return nil, errs.NewServiceUnavailable("characters not indexed yet, retry after the next sync")
+	}
+
+	// the index holds the characters sorted by name (then ID)
+	chars := s.names.All()
+	if field == "modified" {
+		// characters without a modification time come first
+		sort.Slice(chars, func(i, j int) bool {
+			mi, mj := chars[i].Modified, chars[j].Modified
+			switch {
+			case mi == nil || mj == nil:
+				if mi == nil && mj == nil {
+					return chars[i].Id < chars[j].Id
+				}
+				return mi == nil
+			case mi.Equal(*mj):
+				return chars[i].Id < chars[j].Id
+			default:
+				return mi.Before(*mj)
+			}
+		})
+	}
+
+	charIds := make([]int, len(chars))
+	for i, c := range chars {
+		charIds[i] = c.Id
+	}
+	if field != orderBy {
+		for i, j := 0, len(charIds)-1; i < j; i, j = i+1, j-1 {
+			charIds[i], charIds[j] = charIds[j], charIds[i]
+		}
+	}
+	return charIds, nil
+}
+
+// ensureSynced syncs the cache if it has never been synced before, and checks
+// that the cached data is fresh enough to be served.
+func (s *Service) ensureSynced(ctx context.Context) error {
+	// a lookup of the character IDs is a hit if it is answered from the cache,
+	// and a miss if the cache has to be synced on demand first
+	synced := !s.SyncStatus().LastSuccess.IsZero()
+	observeCacheLookup("ids", synced)
+	if !synced {
+		if err := s.SyncCache(ctx); err != nil {
+			if _, cachedLatestModified := s.cache.GetCharacterIds(); cachedLatestModified.IsZero() {
+				return err
+			}
+		}
+	}
+
+	return s.checkStaleness(ctx)
+}
+
+// checkStaleness returns an error if the last sync failed and the cached
+// data is older than the configured maximum staleness.
+func (s *Service) checkStaleness(ctx context.Context) error {
+	status := s.SyncStatus()
+	if !status.Stale() {
+		return nil
+	}
+
+	if !status.LastSuccess.IsZero() && s.cfg.MaxStaleness > 0 {
+		if age := time.Since(status.LastSuccess); age > s.cfg.MaxStaleness {
+			s.logger.Ctx(ctx).Error("cached character IDs too stale to serve", "lastSyncedAgo", age.Round(time.Second))
+			return errs.NewBadGateway("marvel api unavailable and cached data too stale")
+		}
+	}
+
+	s.logger.Ctx(ctx).Warn("serving stale character IDs, last sync failed", "error", status.LastError)
+	return nil
 }
 
-// GetAllCharacterIds returns the character IDs of all Marvel characters.
-func (s *Service) GetAllCharacterIds() ([]int, error) {
+// SyncCache fetches the characters modified since the last sync and adds them to the cache.
+// Concurrent callers share a single sync with Marvel's API.
+func (s *Service) SyncCache(ctx context.Context) error {
+	_, err := s.calls.Do(ctx, "syncCache", func(ctx context.Context) (interface{}, error) {
+		start := time.Now()
+		err := s.syncCache(ctx)
+		observeSync("sync", start, err)
+		s.recordSync(ctx, err)
+		if err == nil {
+			s.saveSnapshotQuietly()
+		}
+		return nil, err
+	})
+	return err
+}
+
+func (s *Service) syncCache(ctx context.Context) error {
+	s.cacheMu.Lock()
+	defer s.cacheMu.Unlock()
+
 	cachedCharIds, cachedLatestModified := s.cache.GetCharacterIds()
 
 	var latestModified *time.Time
@@ -35,9 +248,9 @@ func (s *Service) GetAllCharacterIds() ([]int, error) {
 		latestModified = &cachedLatestModified
 	}
 
-	characters, err := s.client.GetAllCharacters(latestModified)
+	characters, err := s.client.GetAllCharacters(ctx, latestModified)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	if len(characters) > 0 {
@@ -45,45 +258,285 @@ func (s *Service) GetAllCharacterIds() ([]int, error) {
 		// if we really want to be safe, we can add a simple logic here to get the most recent `Modified`
 		newLatestModified, err := time.Parse(dateFormatMarvelApi, characters[0].Modified)
 		if err != nil {
-			return nil, err
+			return err
 		}
 
+		// the cached set may be in use by readers, so changes are made on a copy
+		charIds := cachedCharIds.Copy()
 		for _, c := range characters {
-			cachedCharIds.Add(c.Id)
+			charIds.Add(c.Id)
+		}
+
+		s.cache.SetCharacterIds(*charIds, newLatestModified)
+	}
+
+	chars := toCharacters(characters)
+	s.recordChanges(chars, &cachedCharIds, nil)
+	s.indexCharacters(chars, latestModified == nil)
+
+	return nil
+}
+
+// ReconcileCache fetches the complete list of characters from Marvel's API and makes the
+// cached character IDs match it. Unlike SyncCache, this also drops characters that were
+// removed from Marvel's API. The cache is left untouched if the complete list has fewer
+// characters than the configured ratio of the cached ones.
+func (s *Service) ReconcileCache(ctx context.Context) (*ReconcileResult, error) {
+	result, err := s.calls.Do(ctx, "reconcileCache", func(ctx context.Context) (interface{}, error) {
+		start := time.Now()
+		result, err := s.reconcileCache(ctx)
+		observeSync("reconcile", start, err)
+		if err != nil {
+			s.recordSync(ctx, err)
+			return nil, err
+		}
+		// a skipped reconciliation applied nothing, so it does not count as a successful sync
+		if !result.Skipped {
+			s.recordSync(ctx, nil)
+		}
+
+		s.syncStatusMu.Lock()
+		s.syncStatus.LastReconcile = result
+		s.syncStatusMu.Unlock()
+
+		if !result.Skipped {
+			s.saveSnapshotQuietly()
+		}
+		return result, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result.(*ReconcileResult), nil
+}
+
+func (s *Service) reconcileCache(ctx context.Context) (*ReconcileResult, error) {
+	s.cacheMu.Lock()
+	defer s.cacheMu.Unlock()
+
+	characters, err := s.client.GetAllCharacters(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	fetchedCharIds := NewIntSet()
+	for _, c := range characters {
+		fetchedCharIds.Add(c.Id)
+	}
+
+	cachedCharIds, cachedLatestModified := s.cache.GetCharacterIds()
+
+	result := &ReconcileResult{Time: time.Now()}
+	for _, id := range fetchedCharIds.ToSlice() {
+		if !cachedCharIds.Contains(id) {
+			result.Added++
+		}
+	}
+	removedIds := removedCharIds(&cachedCharIds, fetchedCharIds)
+	result.Removed = len(removedIds)
+
+	minExpected := s.cfg.ReconcileMinRatio * float64(cachedCharIds.Len())
+	if float64(fetchedCharIds.Len()) < minExpected {
+		s.logger.Ctx(ctx).Warn("cache reconciliation skipped, too few characters from marvel api",
+			"fetched", fetchedCharIds.Len(), "cached", cachedCharIds.Len())
+		result.Skipped = true
+		return result, nil
+	}
+
+	latestModified := cachedLatestModified
+	if len(characters) > 0 {
+		// s.client.GetAllCharacters() already returns the latest modified character as the first element
+		latestModified, err = time.Parse(dateFormatMarvelApi, characters[0].Modified)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	s.cache.SetCharacterIds(*fetchedCharIds, latestModified)
+	chars := toCharacters(characters)
+	s.recordChanges(chars, &cachedCharIds, removedIds)
+	s.indexCharacters(chars, true)
+	s.logger.Ctx(ctx).Info("cache reconciled", "added", result.Added, "removed", result.Removed)
+
+	return result, nil
+}
+
+// removedCharIds returns the IDs of the cached characters missing from the complete list of characters.
+func removedCharIds(cachedCharIds, charIds *IntSet) []int {
+	var removed []int
+	for _, id := range cachedCharIds.ToSlice() {
+		if !charIds.Contains(id) {
+			removed = append(removed, id)
+		}
+	}
+	sort.Ints(removed)
+	return removed
+}
+
+// SearchCharacters returns a page of the characters matching the name criteria in `search`
+// (ignoring case), sorted by name. They are looked up in the local name index if it holds
+// every character, or else searched in Marvel's API.
+func (s *Service) SearchCharacters(ctx context.Context, search CharacterSearch) (*CharacterPage, error) {
+	if s.names.Complete() {
+		var matches []*Character
+		if search.Name != "" {
+			matches = s.names.FindByName(search.Name)
+		} else {
+			matches = s.names.FindByNamePrefix(search.NameStartsWith)
+		}
+
+		start, end := search.Offset, search.Offset+search.Limit
+		if start > len(matches) {
+			start = len(matches)
+		}
+		if end > len(matches) {
+			end = len(matches)
 		}
 
-		s.cache.SetCharacterIds(cachedCharIds, newLatestModified)
+		chars := make([]*Character, 0, end-start)
+		for _, c := range matches[start:end] {
+			charCopy := *c
+			chars = append(chars, &charCopy)
+		}
+
+		return &CharacterPage{Total: len(matches), Characters: chars}, nil
+	}
+
+	charData, total, err := s.client.SearchCharacters(ctx, search)
+	if err != nil {
+		return nil, err
+	}
+
+	chars := toCharacters(charData)
+	for _, c := range chars {
+		s.detailCache.SetCharacter(c)
+	}
+
+	return &CharacterPage{Total: total, Characters: chars}, nil
+}
+
+// Search returns a page of the characters matching `query`, ranked by relevance. Query words are
+// matched against the names of the characters (tolerating typos and missing spaces or hyphens)
+// and the words of their descriptions. Like GetAllCharacterIds, the cache is synced on demand
+// if it has never been synced before.
+func (s *Service) Search(ctx context.Context, query string, limit, offset int) (*SearchPage, error) {
+	if err := s.ensureSynced(ctx); err != nil {
+		return nil, err
+	}
+
+	results := s.search.Search(query)
+
+	start, end := offset, offset+limit
+	if start > len(results) {
+		start = len(results)
 	}
+	if end > len(results) {
+		end = len(results)
+	}
+
+	return &SearchPage{Total: len(results), Results: results[start:end]}, nil
+}
+
+// indexCharacters adds `chars` to the name and search indexes. If `full` is true, `chars`
+// is the complete list of characters and replaces the indexed ones altogether, unless it is
+// empty: an index marked complete without any characters would answer every lookup with none.
+func (s *Service) indexCharacters(chars []*Character, full bool) {
+	if full && len(chars) > 0 {
+		s.names.Replace(chars)
+		s.search.Replace(chars)
+	} else {
+		s.names.Upsert(chars)
+		s.search.Upsert(chars)
+	}
+}
 
-	return cachedCharIds.ToSlice(), nil
+// SyncStatus returns the times of the last sync attempt and the last successful sync.
+func (s *Service) SyncStatus() SyncStatus {
+	s.syncStatusMu.RLock()
+	defer s.syncStatusMu.RUnlock()
+	return s.syncStatus
+}
+
+func (s *Service) recordSync(ctx context.Context, err error) {
+	s.syncStatusMu.Lock()
+	defer s.syncStatusMu.Unlock()
+
+	now := time.Now()
+	s.syncStatus.LastAttempt = now
+	if err != nil {
+		s.syncStatus.LastError = err.Error()
+		s.syncStatus.FailedSyncs++
+		s.syncStatus.ConsecutiveFailures++
+		s.logger.Ctx(ctx).Error("cache sync failed", "consecutiveFailures", s.syncStatus.ConsecutiveFailures, "error", err)
+		return
+	}
+	s.syncStatus.LastSuccess = now
+	s.syncStatus.LastError = ""
+	s.syncStatus.ConsecutiveFailures = 0
 }
 
 // GetCharacter returns information about a specific character, given the character's ID.
-func (s *Service) GetCharacter(id int) (*Character, error) {
-	charData, err := s.client.GetCharacter(id)
+// Characters (and IDs not known to Marvel's API) are served from the detail cache when possible,
+// and concurrent callers asking for the same character share a single call to Marvel's API.
+func (s *Service) GetCharacter(ctx context.Context, id int) (*Character, error) {
+	char, err := s.calls.Do(ctx, "character:"+strconv.Itoa(id), func(ctx context.Context) (interface{}, error) {
+		return s.getCharacter(ctx, id)
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	return &Character{
-		Id:          charData.Id,
-		Name:        charData.Name,
-		Description: charData.Description,
-	}, nil
+	charCopy := *char.(*Character)
+	return &charCopy, nil
+}
+
+func (s *Service) getCharacter(ctx context.Context, id int) (*Character, error) {
+	char, ok := s.detailCache.GetCharacter(id)
+	observeCacheLookup("detail", ok)
+	if ok {
+		if char == nil {
+			return nil, errs.NewNotFound("no results")
+		}
+		return char, nil
+	}
+
+	charData, err := s.client.GetCharacter(ctx, id)
+	if err != nil {
+		var notFoundErr *errs.NotFound
+		if errors.As(err, &notFoundErr) {
+			s.detailCache.SetCharacterNotFound(id)
+		}
+		return nil, err
+	}
+
+	char = charData.toCharacter()
+	s.detailCache.SetCharacter(char)
+
+	return char, nil
 }
 
 // ReloadCache fetches all character IDs from Marvel's API and stores
 // them in a cache, along with the latest modified time.
-func (s *Service) ReloadCache() error {
-	characters, err := s.client.GetAllCharacters(nil)
+func (s *Service) ReloadCache(ctx context.Context) (err error) {
+	start := time.Now()
+	defer func() { observeSync("reload", start, err) }()
+
+	s.cacheMu.Lock()
+	defer s.cacheMu.Unlock()
+
+	characters, err := s.client.GetAllCharacters(ctx, nil)
 	if err != nil {
 		return err
 	}
 
+	// an empty list leaves the cache, the change log and the indexes as they were
 	if len(characters) == 0 {
+		s.recordSync(ctx, nil)
 		return nil
 	}
 
+	// parsed before anything is changed, so a failed reload leaves the cache as it was
 	// s.client.GetAllCharacters() already returns the latest modified character as the first element
 	// if we really want to be safe, we can add a simple logic here to get the most recent `Modified`
 	latestModified, err := time.Parse(dateFormatMarvelApi, characters[0].Modified)
@@ -96,6 +549,91 @@ func (s *Service) ReloadCache() error {
 		charIds.Add(c.Id)
 	}
 
+	cachedCharIds, _ := s.cache.GetCharacterIds()
+	chars := toCharacters(characters)
+	s.recordChanges(chars, &cachedCharIds, removedCharIds(&cachedCharIds, charIds))
+	s.indexCharacters(chars, true)
+
 	s.cache.SetCharacterIds(*charIds, latestModified)
+	s.recordSync(ctx, nil)
+	s.saveSnapshotQuietly()
+	return nil
+}
+
+// SaveSnapshot writes the cached character data to the configured snapshot file.
+func (s *Service) SaveSnapshot() error {
+	if s.snapshots == nil {
+		return errors.New("cache snapshots not configured")
+	}
+
+	charIds, latestModified := s.cache.GetCharacterIds()
+
+	return s.snapshots.save(&cacheSnapshot{
+		Version:        snapshotVersion,
+		SavedAt:        time.Now(),
+		LastSynced:     s.SyncStatus().LastSuccess,
+		LatestModified: latestModified,
+		CharacterIds:   charIds.ToSlice(),
+		Characters:     s.detailCache.GetAllEntries(),
+		NameIndex:      s.names.All(),
+		NameIndexFull:  s.names.Complete(),
+		Graph:          s.graph.snapshot(),
+		ChangeLog:      s.changes.snapshot(),
+	})
+}
+
+// RestoreSnapshot populates the cache from the configured snapshot file. It fails if
+// the snapshot is missing, corrupt or written by an incompatible version, in which
+// case the cache should be fully reloaded instead.
+func (s *Service) RestoreSnapshot() error {
+	if s.snapshots == nil {
+		return errors.New("cache snapshots not configured")
+	}
+
+	snapshot, err := s.snapshots.load()
+	if err != nil {
+		return err
+	}
+
+	s.cacheMu.Lock()
+	defer s.cacheMu.Unlock()
+
+	charIds := NewIntSet()
+	for _, id := range snapshot.CharacterIds {
+		charIds.Add(id)
+	}
+	s.cache.SetCharacterIds(*charIds, snapshot.LatestModified)
+
+	s.indexCharacters(snapshot.NameIndex, snapshot.NameIndexFull)
+
+	if snapshot.Graph != nil {
+		s.graph.restore(snapshot.Graph)
+	}
+	if snapshot.ChangeLog != nil {
+		s.changes.restore(snapshot.ChangeLog)
+	}
+
+	// restored in reverse so the most recently used characters stay the most recent,
+	// and until they were due to expire (characters that have expired since are skipped)
+	for i := len(snapshot.Characters) - 1; i >= 0; i-- {
+		cached := snapshot.Characters[i]
+		if time.Now().Before(cached.ExpiresAt) {
+			s.detailCache.SetCharacterUntil(cached.Character, cached.ExpiresAt)
+		}
+	}
+
+	s.syncStatusMu.Lock()
+	s.syncStatus.LastSuccess = snapshot.LastSynced
+	s.syncStatusMu.Unlock()
+
 	return nil
 }
+
+func (s *Service) saveSnapshotQuietly() {
+	if s.snapshots == nil {
+		return
+	}
+	if err := s.SaveSnapshot(); err != nil {
+		s.logger.Error("failed to save cache snapshot", "error", err)
+	}
+}
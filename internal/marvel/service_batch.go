@@ -0,0 +1,73 @@
+package marvel
+
+import (
+	"context"
+	"net/http"
+	"sync"
+
+	"github.com/gkatanacio/marvel-characters-api/internal/errs"
+)
+
+// GetCharacters looks up the characters with the given IDs, fetching those missing from the
+// detail cache concurrently (up to the configured limit). The lookup of each ID fails on its own:
+// the IDs which could not be looked up are listed in the errors of the batch, in request order.
+// Only a cancelled `ctx` fails the whole batch.
+func (s *Service) GetCharacters(ctx context.Context, ids []int) (*CharacterBatch, error) {
+	ids = uniqueInts(ids)
+
+	var slots chan struct{}
+	if s.cfg.BatchConcurrency > 0 {
+		slots = make(chan struct{}, s.cfg.BatchConcurrency)
+	}
+
+	chars := make([]*Character, len(ids))
+	lookupErrs := make([]error, len(ids))
+
+	var wg sync.WaitGroup
+	for i, id := range ids {
+		if slots != nil {
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+				wg.Wait()
+				return nil, ctx.Err()
+			}
+		}
+
+		wg.Add(1)
+		go func(i, id int) {
+			defer wg.Done()
+			if slots != nil {
+				defer func() { <-slots }()
+			}
+			chars[i], lookupErrs[i] = s.GetCharacter(ctx, id)
+		}(i, id)
+	}
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	batch := &CharacterBatch{
+		Characters: make([]*Character, 0, len(ids)),
+		Errors:     make([]*BatchError, 0),
+	}
+	for i, id := range ids {
+		if lookupErrs[i] != nil {
+			batch.Errors = append(batch.Errors, newBatchError(id, lookupErrs[i]))
+			continue
+		}
+		batch.Characters = append(batch.Characters, chars[i])
+	}
+
+	return batch, nil
+}
+
+func newBatchError(id int, err error) *BatchError {
+	status := http.StatusInternalServerError
+	if httpErr, ok := err.(errs.HttpError); ok {
+		status = httpErr.StatusCode()
+	}
+	return &BatchError{Id: id, Status: status, Error: err.Error()}
+}
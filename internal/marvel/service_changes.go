@@ -0,0 +1,75 @@
+package marvel
+
+import (
+	"context"
+	"time"
+)
+
+// GetChanges returns a page of the changes to the characters found by the syncs of the cache
+// with Marvel's API, as selected by `query`. Like GetAllCharacterIds, the cache is synced on demand
+// if it has never been synced before.
+func (s *Service) GetChanges(ctx context.Context, query ChangeQuery) (*ChangePage, error) {
+	if err := s.ensureSynced(ctx); err != nil {
+		return nil, err
+	}
+	return s.changes.Changes(query)
+}
+
+// StreamConfig configures the streams of changes to the characters.
+type StreamConfig struct {
+	// HeartbeatInterval is the time between the comments sent to keep idle streams open.
+	// Zero disables them.
+	HeartbeatInterval time.Duration `envconfig:"HEARTBEAT_INTERVAL" default:"15s"`
+	// BufferSize is the number of changes waiting to be sent to a stream after which the stream
+	// is dropped, so slow consumers never hold up the syncs.
+	BufferSize int `envconfig:"BUFFER_SIZE" default:"100"`
+}
+
+// SubscribeChanges subscribes to the changes to the characters found by the syncs of the cache from
+// now on. If `lastEventId` is given, the changes that followed it are put in the backlog of the subscription.
+func (s *Service) SubscribeChanges(lastEventId string) (*ChangeSubscription, error) {
+	return s.changes.Subscribe(lastEventId, s.cfg.Stream.BufferSize)
+}
+
+// CloseChangeSubscriptions closes every subscription to the changes to the characters,
+// so the streams sending them end.
+func (s *Service) CloseChangeSubscriptions() {
+	s.changes.CloseSubscriptions()
+}
+
+// recordChanges records in the change log the changes made to the cached characters by a sync,
+// given the characters fetched from Marvel's API and the IDs of the characters removed.
+// The changed characters are dropped from the detail cache, so their next request fetches them again.
+// It must be called before the fetched characters are indexed.
+func (s *Service) recordChanges(chars []*Character, cachedCharIds *IntSet, removedIds []int) {
+	var changes []*Change
+	for _, c := range chars {
+		if !cachedCharIds.Contains(c.Id) {
+			changes = append(changes, &Change{Id: c.Id, Type: ChangeAdded, Modified: c.Modified})
+			continue
+		}
+		if old, exists := s.names.Get(c.Id); exists && sameTime(old.Modified, c.Modified) {
+			// Marvel's API returns the characters modified at exactly the time of the last sync again
+			continue
+		}
+		changes = append(changes, &Change{Id: c.Id, Type: ChangeModified, Modified: c.Modified})
+	}
+
+	for _, id := range removedIds {
+		changes = append(changes, &Change{Id: id, Type: ChangeRemoved})
+	}
+
+	for _, c := range changes {
+		// an added character may have been cached as not found
+		s.detailCache.RemoveCharacter(c.Id)
+	}
+
+	s.changes.Record(changes, time.Now())
+}
+
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
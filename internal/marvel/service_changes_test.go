@@ -0,0 +1,87 @@
+package marvel_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gkatanacio/marvel-characters-api/internal/logging"
+	"github.com/gkatanacio/marvel-characters-api/internal/marvel"
+	"github.com/gkatanacio/marvel-characters-api/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_Service_GetChanges_RecordedBySyncs(t *testing.T) {
+	// given
+	var nilTime *time.Time
+	latestModified, _ := time.Parse(time.RFC3339, "2020-07-21T10:33:36-04:00")
+
+	clientMock := new(mocks.MarvelDataFetcher)
+	clientMock.On("GetAllCharacters", mock.Anything, nilTime).Return([]*marvel.MarvelApiCharacterData{
+		{Id: 1009282, Name: "Doctor Strange", Modified: "2020-07-21T10:33:36-0400"},
+		{Id: 1009187, Name: "Black Panther", Modified: "2018-06-19T16:39:46-0400"},
+		{Id: 1009351, Name: "Hulk", Modified: "2018-01-01T10:00:00-0400"},
+	}, nil).Once()
+	clientMock.On("GetAllCharacters", mock.Anything, &latestModified).Return([]*marvel.MarvelApiCharacterData{
+		{Id: 1009610, Name: "Spider-Man", Modified: "2021-02-01T10:00:00-0400"},
+		{Id: 1009187, Name: "Black Panther", Modified: "2021-01-01T10:00:00-0400"},
+		{Id: 1009282, Name: "Doctor Strange", Modified: "2020-07-21T10:33:36-0400"},
+	}, nil).Once()
+	clientMock.On("GetAllCharacters", mock.Anything, nilTime).Return([]*marvel.MarvelApiCharacterData{
+		{Id: 1009610, Name: "Spider-Man", Modified: "2021-02-01T10:00:00-0400"},
+		{Id: 1009282, Name: "Doctor Strange", Modified: "2020-07-21T10:33:36-0400"},
+		{Id: 1009187, Name: "Black Panther", Modified: "2021-01-01T10:00:00-0400"},
+	}, nil).Once()
+
+	service := marvel.NewService(&marvel.Config{ReconcileMinRatio: 0.5}, clientMock, marvel.NewInMemCache(), marvel.NewInMemDetailCache(marvel.DetailCacheConfig{}), logging.Discard())
+	assert.NoError(t, service.ReloadCache(context.Background()))
+	since := time.Now()
+	assert.NoError(t, service.SyncCache(context.Background()))
+	_, err := service.ReconcileCache(context.Background())
+	assert.NoError(t, err)
+
+	// when
+	page, err := service.GetChanges(context.Background(), marvel.ChangeQuery{Since: since})
+
+	// then
+	assert.NoError(t, err)
+	var changes []marvel.ChangeType
+	for _, c := range page.Changes {
+		changes = append(changes, c.Type)
+	}
+	assert.Equal(t, []int{1009610, 1009187, 1009351}, changeIds(page))
+	assert.Equal(t, []marvel.ChangeType{marvel.ChangeAdded, marvel.ChangeModified, marvel.ChangeRemoved}, changes)
+	assert.Equal(t, "2021-02-01T14:00:00Z", page.Changes[0].Modified.UTC().Format(time.RFC3339))
+	assert.Nil(t, page.Changes[2].Modified)
+	clientMock.AssertExpectations(t)
+}
+
+func Test_Service_GetChanges_NoneRecordedByEmptyReload(t *testing.T) {
+	// given
+	var nilTime *time.Time
+
+	clientMock := new(mocks.MarvelDataFetcher)
+	clientMock.On("GetAllCharacters", mock.Anything, nilTime).Return([]*marvel.MarvelApiCharacterData{
+		{Id: 1009282, Name: "Doctor Strange", Modified: "2020-07-21T10:33:36-0400"},
+		{Id: 1009187, Name: "Black Panther", Modified: "2018-06-19T16:39:46-0400"},
+	}, nil).Once()
+	clientMock.On("GetAllCharacters", mock.Anything, nilTime).Return([]*marvel.MarvelApiCharacterData{}, nil).Once()
+
+	cache := marvel.NewInMemCache()
+	service := marvel.NewService(new(marvel.Config), clientMock, cache, marvel.NewInMemDetailCache(marvel.DetailCacheConfig{}), logging.Discard())
+	assert.NoError(t, service.ReloadCache(context.Background()))
+	since := time.Now()
+
+	// when
+	err := service.ReloadCache(context.Background())
+
+	// then
+	assert.NoError(t, err)
+	page, err := service.GetChanges(context.Background(), marvel.ChangeQuery{Since: since})
+	assert.NoError(t, err)
+	assert.Empty(t, page.Changes)
+	charIds, _ := cache.GetCharacterIds()
+	assert.ElementsMatch(t, []int{1009282, 1009187}, charIds.ToSlice())
+	clientMock.AssertExpectations(t)
+}
@@ -0,0 +1,147 @@
+package marvel
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/gkatanacio/marvel-characters-api/internal/errs"
+)
+
+// graphCrawl is the progress of a sync of the co-appearance graph through the pages of comics.
+type graphCrawl struct {
+	modifiedSince *time.Time
+	// latestModified is the modification time of the most recently modified comic, from the first page
+	latestModified time.Time
+	// offset is the number of comics already added to the graph
+	offset int
+}
+
+// SyncGraph fetches the characters of the comics modified since the last sync of the co-appearance
+// graph and updates the graph with them, one page at a time. The first sync builds the graph from
+// every comic. A sync that fails part way is resumed from the page it stopped at by the next one.
+// Concurrent callers share a single sync with Marvel's API.
+func (s *Service) SyncGraph(ctx context.Context) error {
+	_, err := s.calls.Do(ctx, "syncGraph", func(ctx context.Context) (result interface{}, err error) {
+		start := time.Now()
+		defer func() { observeSync("graph", start, err) }()
+
+		// only touched here, and syncs never run concurrently
+		crawl := s.graphCrawl
+		if crawl == nil {
+			crawl = &graphCrawl{}
+			if latestModified := s.graph.LatestModified(); !latestModified.IsZero() {
+				crawl.modifiedSince = &latestModified
+			}
+		} else {
+			s.logger.Ctx(ctx).Info("resuming co-appearance graph sync", "offset", crawl.offset)
+		}
+
+		for {
+			comicChars, total, err := s.client.GetComicCharactersPage(ctx, crawl.modifiedSince, crawl.offset)
+			if err != nil {
+				s.graphCrawl = crawl
+				s.logger.Ctx(ctx).Error("co-appearance graph sync failed", "offset", crawl.offset, "error", err)
+				return nil, err
+			}
+
+			if crawl.offset == 0 && len(comicChars) > 0 {
+				// comics are ordered from the most recently modified
+				crawl.latestModified, err = time.Parse(dateFormatMarvelApi, comicChars[0].Modified)
+				if err != nil {
+					s.graphCrawl = nil
+					return nil, err
+				}
+			}
+
+			// the graph is only moved forward once every page is in, so it is not considered built before
+			s.graph.Update(comicChars, time.Time{})
+			crawl.offset += len(comicChars)
+
+			if len(comicChars) == 0 || crawl.offset >= total {
+				break
+			}
+		}
+
+		latestModified := crawl.latestModified
+		if latestModified.IsZero() && s.graph.LatestModified().IsZero() {
+			// no comics at all, but the graph is still built (empty)
+			latestModified = time.Now()
+		}
+
+		s.graph.Update(nil, latestModified)
+		s.graphCrawl = nil
+		s.logger.Ctx(ctx).Info("co-appearance graph synced", "comicsUpdated", crawl.offset)
+
+		s.saveSnapshotQuietly()
+		return nil, nil
+	})
+	return err
+}
+
+// GetConnections returns a page of the characters sharing comics with the character with the given ID,
+// from the one sharing the most comics to the one sharing the least, along with the total number of them.
+func (s *Service) GetConnections(ctx context.Context, id int, query ResourceQuery) ([]*Connection, int, error) {
+	if err := s.checkGraphBuilt(); err != nil {
+		return nil, 0, err
+	}
+
+	if !s.graph.Contains(id) {
+		// a known character may just not appear in any comic
+		if _, exists := s.names.Get(id); !exists {
+			return nil, 0, errs.NewNotFound("no results")
+		}
+	}
+
+	conns := s.graph.Connections(id)
+
+	start, end := query.Offset, query.Offset+query.Limit
+	if start > len(conns) {
+		start = len(conns)
+	}
+	if end > len(conns) {
+		end = len(conns)
+	}
+
+	page := conns[start:end]
+	s.nameConnections(page)
+	return page, len(conns), nil
+}
+
+// GetPath returns the shortest chain of characters linking the characters with the given IDs
+// through shared comics, including both of them.
+func (s *Service) GetPath(ctx context.Context, from, to int) ([]*Connection, error) {
+	if err := s.checkGraphBuilt(); err != nil {
+		return nil, err
+	}
+
+	for _, id := range []int{from, to} {
+		if !s.graph.Contains(id) {
+			return nil, errs.NewNotFound("no comics found for character " + strconv.Itoa(id))
+		}
+	}
+
+	path, ok := s.graph.ShortestPath(from, to)
+	if !ok {
+		return nil, errs.NewNotFound("characters are not connected")
+	}
+
+	s.nameConnections(path)
+	return path, nil
+}
+
+func (s *Service) checkGraphBuilt() error {
+	if s.graph.LatestModified().IsZero() {
+		return errs.NewServiceUnavailable("co-appearance graph not built yet")
+	}
+	return nil
+}
+
+// nameConnections sets the names of the connected characters, from the name index.
+func (s *Service) nameConnections(conns []*Connection) {
+	for _, c := range conns {
+		if char, exists := s.names.Get(c.Id); exists {
+			c.Name = char.Name
+		}
+	}
+}
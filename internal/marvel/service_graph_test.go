@@ -0,0 +1,147 @@
+package marvel_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gkatanacio/marvel-characters-api/internal/errs"
+	"github.com/gkatanacio/marvel-characters-api/internal/logging"
+	"github.com/gkatanacio/marvel-characters-api/internal/marvel"
+	"github.com/gkatanacio/marvel-characters-api/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_Service_GetConnections_GraphNotBuilt(t *testing.T) {
+	// given
+	service := testService(new(mocks.MarvelDataFetcher), marvel.NewInMemCache())
+
+	// when
+	_, _, err := service.GetConnections(context.Background(), 1009351, marvel.ResourceQuery{Limit: 20})
+
+	// then
+	assert.IsType(t, &errs.ServiceUnavailable{}, err)
+}
+
+func Test_Service_SyncGraph_Incremental(t *testing.T) {
+	// given
+	var nilTime *time.Time
+	latestModified, _ := time.Parse("2006-01-02T15:04:05-0700", "2021-03-01T10:00:00-0500")
+
+	clientMock := new(mocks.MarvelDataFetcher)
+	clientMock.On("GetAllCharacters", mock.Anything, nilTime).Return([]*marvel.MarvelApiCharacterData{
+		{Id: 1009351, Name: "Hulk", Modified: "2020-07-21T10:35:15-0400"},
+		{Id: 1009610, Name: "Spider-Man", Modified: "2020-07-21T10:30:10-0400"},
+	}, nil)
+	clientMock.On("GetComicCharactersPage", mock.Anything, nilTime, 0).Return([]*marvel.ComicCharacters{
+		{ComicId: 1, Modified: "2021-03-01T10:00:00-0500", CharacterIds: []int{1009351, 1009610}},
+		{ComicId: 2, Modified: "2020-01-01T10:00:00-0500", CharacterIds: []int{1009351, 1009610, 1009368}},
+	}, 2, nil).Once()
+	clientMock.On("GetComicCharactersPage", mock.Anything, &latestModified, 0).Return([]*marvel.ComicCharacters{
+		{ComicId: 2, Modified: "2021-03-02T10:00:00-0500", CharacterIds: []int{1009351, 1009368}},
+	}, 1, nil).Once()
+
+	service := testService(clientMock, marvel.NewInMemCache())
+	assert.NoError(t, service.ReloadCache(context.Background()))
+	assert.NoError(t, service.SyncGraph(context.Background()))
+
+	// when
+	err := service.SyncGraph(context.Background())
+
+	// then
+	assert.NoError(t, err)
+	conns, total, err := service.GetConnections(context.Background(), 1009351, marvel.ResourceQuery{Limit: 20})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, total)
+	assert.Equal(t, []*marvel.Connection{
+		{Id: 1009368, SharedComics: 1},
+		{Id: 1009610, Name: "Spider-Man", SharedComics: 1},
+	}, conns)
+	clientMock.AssertExpectations(t)
+}
+
+func Test_Service_GetConnections_UnknownChar(t *testing.T) {
+	// given
+	var nilTime *time.Time
+
+	clientMock := new(mocks.MarvelDataFetcher)
+	clientMock.On("GetComicCharactersPage", mock.Anything, nilTime, 0).Return([]*marvel.ComicCharacters{
+		{ComicId: 1, Modified: "2021-03-01T10:00:00-0500", CharacterIds: []int{1009351, 1009610}},
+	}, 1, nil)
+
+	service := testService(clientMock, marvel.NewInMemCache())
+	assert.NoError(t, service.SyncGraph(context.Background()))
+
+	// when
+	_, _, err := service.GetConnections(context.Background(), 1, marvel.ResourceQuery{Limit: 20})
+
+	// then
+	assert.IsType(t, &errs.NotFound{}, err)
+}
+
+func Test_Service_GetPath_RestoredFromSnapshot(t *testing.T) {
+	// given
+	var nilTime *time.Time
+
+	clientMock := new(mocks.MarvelDataFetcher)
+	clientMock.On("GetComicCharactersPage", mock.Anything, nilTime, 0).Return([]*marvel.ComicCharacters{
+		{ComicId: 1, Modified: "2021-03-01T10:00:00-0500", CharacterIds: []int{1009351, 1009610}},
+		{ComicId: 2, Modified: "2020-01-01T10:00:00-0500", CharacterIds: []int{1009610, 1009368}},
+		{ComicId: 3, Modified: "2020-01-01T10:00:00-0500", CharacterIds: []int{1009282}},
+	}, 3, nil)
+
+	cfg := &marvel.Config{SnapshotPath: filepath.Join(t.TempDir(), "snapshot.json")}
+	service := marvel.NewService(cfg, clientMock, marvel.NewInMemCache(), marvel.NewInMemDetailCache(marvel.DetailCacheConfig{}), logging.Discard())
+	assert.NoError(t, service.SyncGraph(context.Background()))
+
+	restoredService := marvel.NewService(cfg, new(mocks.MarvelDataFetcher), marvel.NewInMemCache(), marvel.NewInMemDetailCache(marvel.DetailCacheConfig{}), logging.Discard())
+	assert.NoError(t, restoredService.RestoreSnapshot())
+
+	// when
+	path, err := restoredService.GetPath(context.Background(), 1009351, 1009368)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, []*marvel.Connection{
+		{Id: 1009351},
+		{Id: 1009610, SharedComics: 1},
+		{Id: 1009368, SharedComics: 1},
+	}, path)
+	_, err = restoredService.GetPath(context.Background(), 1009351, 1009282)
+	assert.IsType(t, &errs.NotFound{}, err)
+}
+
+func Test_Service_SyncGraph_ResumedAfterFailure(t *testing.T) {
+	// given
+	var nilTime *time.Time
+
+	clientMock := new(mocks.MarvelDataFetcher)
+	clientMock.On("GetComicCharactersPage", mock.Anything, nilTime, 0).Return([]*marvel.ComicCharacters{
+		{ComicId: 1, Modified: "2021-03-01T10:00:00-0500", CharacterIds: []int{1009351, 1009610}},
+	}, 2, nil).Once()
+	clientMock.On("GetComicCharactersPage", mock.Anything, nilTime, 1).Return(nil, 0, errs.NewBadGateway("error response from marvel api")).Once()
+	clientMock.On("GetComicCharactersPage", mock.Anything, nilTime, 1).Return([]*marvel.ComicCharacters{
+		{ComicId: 2, Modified: "2020-01-01T10:00:00-0500", CharacterIds: []int{1009610, 1009368}},
+	}, 2, nil).Once()
+
+	service := testService(clientMock, marvel.NewInMemCache())
+	assert.Error(t, service.SyncGraph(context.Background()))
+	_, _, err := service.GetConnections(context.Background(), 1009610, marvel.ResourceQuery{Limit: 20})
+	assert.IsType(t, &errs.ServiceUnavailable{}, err, "graph not built until every page is in")
+
+	// when
+	err = service.SyncGraph(context.Background())
+
+	// then
+	assert.NoError(t, err)
+	conns, total, err := service.GetConnections(context.Background(), 1009610, marvel.ResourceQuery{Limit: 20})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, total)
+	assert.Equal(t, []*marvel.Connection{
+		{Id: 1009351, SharedComics: 1},
+		{Id: 1009368, SharedComics: 1},
+	}, conns)
+	clientMock.AssertExpectations(t)
+}
@@ -0,0 +1,247 @@
+package marvel
+
+import (
+	"context"
+	"errors"
+
+	"github.com/gkatanacio/marvel-characters-api/internal/errs"
+)
+
+// GetComics returns a page of comics, along with the total number of comics.
+func (s *Service) GetComics(ctx context.Context, query ResourceQuery) ([]*Comic, int, error) {
+	comicData, total, err := s.client.GetComics(ctx, query)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	comics := make([]*Comic, 0, len(comicData))
+	for _, d := range comicData {
+		comics = append(comics, d.toComic())
+	}
+	return comics, total, nil
+}
+
+// GetComic returns information about a specific comic, given the comic's ID.
+func (s *Service) GetComic(ctx context.Context, id int) (*Comic, error) {
+	comicData, err := s.client.GetComic(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return comicData.toComic(), nil
+}
+
+// GetSeriesList returns a page of series, along with the total number of series.
+func (s *Service) GetSeriesList(ctx context.Context, query ResourceQuery) ([]*Series, int, error) {
+	seriesData, total, err := s.client.GetSeriesList(ctx, query)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	series := make([]*Series, 0, len(seriesData))
+	for _, d := range seriesData {
+		series = append(series, d.toSeries())
+	}
+	return series, total, nil
+}
+
+// GetSeries returns information about a specific series, given the series' ID.
+func (s *Service) GetSeries(ctx context.Context, id int) (*Series, error) {
+	seriesData, err := s.client.GetSeries(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return seriesData.toSeries(), nil
+}
+
+// GetEvents returns a page of events, along with the total number of events.
+func (s *Service) GetEvents(ctx context.Context, query ResourceQuery) ([]*Event, int, error) {
+	eventData, total, err := s.client.GetEvents(ctx, query)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	events := make([]*Event, 0, len(eventData))
+	for _, d := range eventData {
+		events = append(events, d.toEvent())
+	}
+	return events, total, nil
+}
+
+// GetEvent returns information about a specific event, given the event's ID.
+func (s *Service) GetEvent(ctx context.Context, id int) (*Event, error) {
+	eventData, err := s.client.GetEvent(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return eventData.toEvent(), nil
+}
+
+// GetStories returns a page of stories, along with the total number of stories.
+func (s *Service) GetStories(ctx context.Context, query ResourceQuery) ([]*Story, int, error) {
+	storyData, total, err := s.client.GetStories(ctx, query)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	stories := make([]*Story, 0, len(storyData))
+	for _, d := range storyData {
+		stories = append(stories, d.toStory())
+	}
+	return stories, total, nil
+}
+
+// GetStory returns information about a specific story, given the story's ID.
+func (s *Service) GetStory(ctx context.Context, id int) (*Story, error) {
+	storyData, err := s.client.GetStory(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return storyData.toStory(), nil
+}
+
+// GetCreators returns a page of creators, along with the total number of creators.
+func (s *Service) GetCreators(ctx context.Context, query ResourceQuery) ([]*Creator, int, error) {
+	creatorData, total, err := s.client.GetCreators(ctx, query)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	creators := make([]*Creator, 0, len(creatorData))
+	for _, d := range creatorData {
+		creators = append(creators, d.toCreator())
+	}
+	return creators, total, nil
+}
+
+// GetCreator returns information about a specific creator, given the creator's ID.
+func (s *Service) GetCreator(ctx context.Context, id int) (*Creator, error) {
+	creatorData, err := s.client.GetCreator(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return creatorData.toCreator(), nil
+}
+
+// GetCharacterComics returns a page of the comics the character with the given ID appears in,
+// along with the total number of such comics. Pages are cached per character and query.
+func (s *Service) GetCharacterComics(ctx context.Context, id int, query ResourceQuery) ([]*Comic, int, error) {
+	page, err := s.getRelated(ctx, "comics", id, query, func(ctx context.Context) (*relatedPage, error) {
+		comicData, total, err := s.client.GetCharacterComics(ctx, id, query)
+		if err != nil {
+			return nil, err
+		}
+
+		comics := make([]*Comic, 0, len(comicData))
+		for _, d := range comicData {
+			comics = append(comics, d.toComic())
+		}
+		return &relatedPage{items: comics, total: total}, nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return append([]*Comic{}, page.items.([]*Comic)...), page.total, nil
+}
+
+// GetCharacterSeries returns a page of the series the character with the given ID appears in,
+// along with the total number of such series. Pages are cached per character and query.
+func (s *Service) GetCharacterSeries(ctx context.Context, id int, query ResourceQuery) ([]*Series, int, error) {
+	page, err := s.getRelated(ctx, "series", id, query, func(ctx context.Context) (*relatedPage, error) {
+		seriesData, total, err := s.client.GetCharacterSeries(ctx, id, query)
+		if err != nil {
+			return nil, err
+		}
+
+		series := make([]*Series, 0, len(seriesData))
+		for _, d := range seriesData {
+			series = append(series, d.toSeries())
+		}
+		return &relatedPage{items: series, total: total}, nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return append([]*Series{}, page.items.([]*Series)...), page.total, nil
+}
+
+// GetCharacterEvents returns a page of the events the character with the given ID appears in,
+// along with the total number of such events. Pages are cached per character and query.
+func (s *Service) GetCharacterEvents(ctx context.Context, id int, query ResourceQuery) ([]*Event, int, error) {
+	page, err := s.getRelated(ctx, "events", id, query, func(ctx context.Context) (*relatedPage, error) {
+		eventData, total, err := s.client.GetCharacterEvents(ctx, id, query)
+		if err != nil {
+			return nil, err
+		}
+
+		events := make([]*Event, 0, len(eventData))
+		for _, d := range eventData {
+			events = append(events, d.toEvent())
+		}
+		return &relatedPage{items: events, total: total}, nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return append([]*Event{}, page.items.([]*Event)...), page.total, nil
+}
+
+// GetCharacterStories returns a page of the stories the character with the given ID appears in,
+// along with the total number of such stories. Pages are cached per character and query.
+func (s *Service) GetCharacterStories(ctx context.Context, id int, query ResourceQuery) ([]*Story, int, error) {
+	page, err := s.getRelated(ctx, "stories", id, query, func(ctx context.Context) (*relatedPage, error) {
+		storyData, total, err := s.client.GetCharacterStories(ctx, id, query)
+		if err != nil {
+			return nil, err
+		}
+
+		stories := make([]*Story, 0, len(storyData))
+		for _, d := range storyData {
+			stories = append(stories, d.toStory())
+		}
+		return &relatedPage{items: stories, total: total}, nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return append([]*Story{}, page.items.([]*Story)...), page.total, nil
+}
+
+// getRelated returns a page of `resource` related to the character with the given ID, from the
+// cache if possible or else by calling `fetch`. Concurrent callers asking for the same page share
+// a single call to Marvel's API. Character IDs known not to exist are not looked up again.
+func (s *Service) getRelated(ctx context.Context, resource string, id int, query ResourceQuery, fetch func(ctx context.Context) (*relatedPage, error)) (*relatedPage, error) {
+	key := relatedCacheKey(resource, id, query)
+
+	page, err := s.calls.Do(ctx, key, func(ctx context.Context) (interface{}, error) {
+		page, ok := s.related.get(key)
+		observeCacheLookup("related", ok)
+		if ok {
+			return page, nil
+		}
+
+		if char, ok := s.detailCache.GetCharacter(id); ok && char == nil {
+			return nil, errs.NewNotFound("no results")
+		}
+
+		page, err := fetch(ctx)
+		if err != nil {
+			var notFoundErr *errs.NotFound
+			if errors.As(err, &notFoundErr) {
+				s.detailCache.SetCharacterNotFound(id)
+			}
+			return nil, err
+		}
+
+		s.related.set(key, page)
+		return page, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return page.(*relatedPage), nil
+}
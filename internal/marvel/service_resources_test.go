@@ -0,0 +1,112 @@
+package marvel_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gkatanacio/marvel-characters-api/internal/errs"
+	"github.com/gkatanacio/marvel-characters-api/internal/logging"
+	"github.com/gkatanacio/marvel-characters-api/internal/marvel"
+	"github.com/gkatanacio/marvel-characters-api/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_Service_GetComic_HappyPath(t *testing.T) {
+	// given
+	clientMock := new(mocks.MarvelDataFetcher)
+	clientMock.On("GetComic", mock.Anything, 41112).Return(&marvel.MarvelApiComicData{
+		Id:       41112,
+		Title:    "5 Ronin (Marvel Edition) (2011) #2",
+		Modified: "-0001-11-30T00:00:00-0500",
+		Dates: []*marvel.MarvelApiDate{
+			{Type: "onsaleDate", Date: "2011-03-02T00:00:00-0500"},
+		},
+		Thumbnail: &marvel.MarvelApiImage{Path: "http://i.annihil.us/u/prod/marvel/i/mg/c/e0/4d5ab1f5a8b5e", Extension: "jpg"},
+	}, nil)
+
+	service := testService(clientMock, marvel.NewInMemCache())
+
+	// when
+	comic, err := service.GetComic(context.Background(), 41112)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, "5 Ronin (Marvel Edition) (2011) #2", comic.Title)
+	assert.Nil(t, comic.Modified)
+	assert.Equal(t, "onsaleDate", comic.Dates[0].Type)
+	assert.Equal(t, "2011-03-02T05:00:00Z", comic.Dates[0].Date.UTC().Format(time.RFC3339))
+	assert.Equal(t, "http://i.annihil.us/u/prod/marvel/i/mg/c/e0/4d5ab1f5a8b5e.jpg", comic.Thumbnail.Url)
+	clientMock.AssertExpectations(t)
+}
+
+func Test_Service_GetEvents_HappyPath(t *testing.T) {
+	// given
+	query := marvel.ResourceQuery{Limit: 20}
+
+	clientMock := new(mocks.MarvelDataFetcher)
+	clientMock.On("GetEvents", mock.Anything, query).Return([]*marvel.MarvelApiEventData{
+		{Id: 116, Title: "Acts of Vengeance!", Start: "1989-12-10 00:00:00", End: "2008-01-04 00:00:00"},
+	}, 74, nil)
+
+	service := testService(clientMock, marvel.NewInMemCache())
+
+	// when
+	events, total, err := service.GetEvents(context.Background(), query)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, 74, total)
+	assert.Equal(t, "Acts of Vengeance!", events[0].Title)
+	assert.Equal(t, "1989-12-10", events[0].Start.Format("2006-01-02"))
+	assert.Equal(t, "2008-01-04", events[0].End.Format("2006-01-02"))
+	clientMock.AssertExpectations(t)
+}
+
+func Test_Service_GetCharacterComics_Cached(t *testing.T) {
+	// given
+	query := marvel.ResourceQuery{Limit: 20, OrderBy: "-onsaleDate", Format: "comic"}
+
+	clientMock := new(mocks.MarvelDataFetcher)
+	clientMock.On("GetCharacterComics", mock.Anything, 1009351, query).Return([]*marvel.MarvelApiComicData{
+		{Id: 41112, Title: "5 Ronin (Marvel Edition) (2011) #2"},
+	}, 1661, nil).Once()
+
+	cfg := &marvel.Config{RelatedCache: marvel.RelatedCacheConfig{TTL: time.Minute}}
+	service := marvel.NewService(cfg, clientMock, marvel.NewInMemCache(), marvel.NewInMemDetailCache(marvel.DetailCacheConfig{}), logging.Discard())
+
+	// when
+	comics1, total1, err1 := service.GetCharacterComics(context.Background(), 1009351, query)
+	comics2, total2, err2 := service.GetCharacterComics(context.Background(), 1009351, query)
+
+	// then
+	assert.NoError(t, err1)
+	assert.NoError(t, err2)
+	assert.Equal(t, 1661, total1)
+	assert.Equal(t, total1, total2)
+	assert.Equal(t, comics1, comics2)
+	assert.Equal(t, "5 Ronin (Marvel Edition) (2011) #2", comics1[0].Title)
+	clientMock.AssertExpectations(t)
+}
+
+func Test_Service_GetCharacterSeries_CharacterNotFound(t *testing.T) {
+	// given
+	query := marvel.ResourceQuery{Limit: 20}
+
+	clientMock := new(mocks.MarvelDataFetcher)
+	clientMock.On("GetCharacterSeries", mock.Anything, 1, query).Return(nil, 0, errs.NewNotFound("no results")).Once()
+
+	detailCache := marvel.NewInMemDetailCache(marvel.DetailCacheConfig{NotFoundTTL: time.Minute})
+	service := marvel.NewService(new(marvel.Config), clientMock, marvel.NewInMemCache(), detailCache, logging.Discard())
+
+	// when
+	_, _, err1 := service.GetCharacterSeries(context.Background(), 1, query)
+	_, _, err2 := service.GetCharacterEvents(context.Background(), 1, query)
+
+	// then
+	assert.IsType(t, &errs.NotFound{}, err1)
+	assert.IsType(t, &errs.NotFound{}, err2)
+	clientMock.AssertExpectations(t)
+	clientMock.AssertNotCalled(t, "GetCharacterEvents", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -1,21 +1,37 @@
 package marvel_test
 
 import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/gkatanacio/marvel-characters-api/internal/errs"
+	"github.com/gkatanacio/marvel-characters-api/internal/logging"
 	"github.com/gkatanacio/marvel-characters-api/internal/marvel"
 	"github.com/gkatanacio/marvel-characters-api/mocks"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
+func testService(client marvel.MarvelDataFetcher, cache marvel.CharacterCache) *marvel.Service {
+	return marvel.NewService(new(marvel.Config), client, cache, marvel.NewInMemDetailCache(marvel.DetailCacheConfig{}), logging.Discard())
+}
+
 func Test_Service_GetAllCharacterIds_NewCharsFetched(t *testing.T) {
 	// given
 	var latestModified *time.Time
 
 	clientMock := new(mocks.MarvelDataFetcher)
-	clientMock.On("GetAllCharacters", latestModified).Return([]*marvel.MarvelApiCharacterData{
+	clientMock.On("GetAllCharacters", mock.Anything, latestModified).Return([]*marvel.MarvelApiCharacterData{
 		{
 			Id:       1009282,
 			Name:     "Doctor Strange",
@@ -28,10 +44,10 @@ func Test_Service_GetAllCharacterIds_NewCharsFetched(t *testing.T) {
 		},
 	}, nil)
 
-	service := marvel.NewService(clientMock, marvel.NewInMemCache())
+	service := testService(clientMock, marvel.NewInMemCache())
 
 	// when
-	charIds, err := service.GetAllCharacterIds()
+	charIds, err := service.GetAllCharacterIds(context.Background())
 
 	// then
 	assert.NoError(t, err)
@@ -45,7 +61,7 @@ func Test_Service_GetAllCharacterIds_NoCharsFetched(t *testing.T) {
 	latestModified := time.Now()
 
 	clientMock := new(mocks.MarvelDataFetcher)
-	clientMock.On("GetAllCharacters", &latestModified).Return(nil, nil)
+	clientMock.On("GetAllCharacters", mock.Anything, &latestModified).Return(nil, nil)
 
 	cachedIds := marvel.NewIntSet()
 	cachedIds.Add(1009351)
@@ -53,10 +69,10 @@ func Test_Service_GetAllCharacterIds_NoCharsFetched(t *testing.T) {
 	cachedIds.Add(1011001)
 	cache := marvel.NewInMemCache()
 	cache.SetCharacterIds(*cachedIds, latestModified)
-	service := marvel.NewService(clientMock, cache)
+	service := testService(clientMock, cache)
 
 	// when
-	charIds, err := service.GetAllCharacterIds()
+	charIds, err := service.GetAllCharacterIds(context.Background())
 
 	// then
 	assert.NoError(t, err)
@@ -70,26 +86,50 @@ func Test_Service_GetCharacter_Found(t *testing.T) {
 	charId := 1009610
 
 	clientMock := new(mocks.MarvelDataFetcher)
-	clientMock.On("GetCharacter", charId).Return(&marvel.MarvelApiCharacterData{
+	clientMock.On("GetCharacter", mock.Anything, charId).Return(&marvel.MarvelApiCharacterData{
 		Id:          charId,
 		Name:        "Spider-Man",
 		Description: "Bitten by a radioactive spider, high school student Peter Parker gained the speed, strength and powers of a spider.",
 		Modified:    "2020-07-21T10:30:10-0400",
+		ResourceURI: "http://gateway.marvel.com/v1/public/characters/1009610",
+		Thumbnail: &marvel.MarvelApiImage{
+			Path:      "http://i.annihil.us/u/prod/marvel/i/mg/3/50/526548a343e4b",
+			Extension: "jpg",
+		},
+		Urls: []*marvel.Link{
+			{Type: "wiki", Url: "http://marvel.com/universe/Spider-Man_(Peter_Parker)"},
+		},
+		Comics: &marvel.ResourceList{
+			Available:     1,
+			Returned:      1,
+			CollectionURI: "http://gateway.marvel.com/v1/public/characters/1009610/comics",
+			Items: []*marvel.ResourceSummary{
+				{ResourceURI: "http://gateway.marvel.com/v1/public/comics/62304", Name: "Spider-Man (2016) #1"},
+			},
+		},
 	}, nil)
 
-	service := marvel.NewService(clientMock, marvel.NewInMemCache())
+	service := testService(clientMock, marvel.NewInMemCache())
 
 	// when
-	character, err := service.GetCharacter(charId)
+	character, err := service.GetCharacter(context.Background(), charId)
 
 	// then
 	assert.NoError(t, err)
 	assert.NotNil(t, character)
-	assert.Equal(t, marvel.Character{
-		Id:          character.Id,
-		Name:        character.Name,
-		Description: character.Description,
-	}, *character)
+	assert.Equal(t, charId, character.Id)
+	assert.Equal(t, "Spider-Man", character.Name)
+	assert.Equal(t, "2020-07-21T14:30:10Z", character.Modified.UTC().Format(time.RFC3339))
+	assert.Equal(t, "http://gateway.marvel.com/v1/public/characters/1009610", character.ResourceURI)
+	assert.Equal(t, &marvel.Thumbnail{
+		Path:      "http://i.annihil.us/u/prod/marvel/i/mg/3/50/526548a343e4b",
+		Extension: "jpg",
+		Url:       "http://i.annihil.us/u/prod/marvel/i/mg/3/50/526548a343e4b.jpg",
+	}, character.Thumbnail)
+	assert.Equal(t, "wiki", character.Urls[0].Type)
+	assert.Equal(t, 1, character.Comics.Available)
+	assert.Equal(t, "Spider-Man (2016) #1", character.Comics.Items[0].Name)
+	assert.Nil(t, character.Series)
 	clientMock.AssertExpectations(t)
 }
 
@@ -98,12 +138,12 @@ func Test_Service_GetCharacter_NotFound(t *testing.T) {
 	charId := 9111111
 
 	clientMock := new(mocks.MarvelDataFetcher)
-	clientMock.On("GetCharacter", charId).Return(nil, errs.NewNotFound("no results"))
+	clientMock.On("GetCharacter", mock.Anything, charId).Return(nil, errs.NewNotFound("no results"))
 
-	service := marvel.NewService(clientMock, marvel.NewInMemCache())
+	service := testService(clientMock, marvel.NewInMemCache())
 
 	// when
-	_, err := service.GetCharacter(charId)
+	_, err := service.GetCharacter(context.Background(), charId)
 
 	// then
 	assert.Error(t, err)
@@ -116,7 +156,220 @@ func Test_Service_ReloadCache_HappyPath(t *testing.T) {
 	var nilTime *time.Time
 
 	clientMock := new(mocks.MarvelDataFetcher)
-	clientMock.On("GetAllCharacters", nilTime).Return([]*marvel.MarvelApiCharacterData{
+	clientMock.On("GetAllCharacters", mock.Anything, nilTime).Return([]*marvel.MarvelApiCharacterData{
+		{
+			Id:       1009282,
+			Name:     "Doctor Strange",
+			Modified: "2020-07-21T10:33:36-0400",
+		},
+	}, nil)
+
+	service := testService(clientMock, marvel.NewInMemCache())
+
+	// when
+	err := service.ReloadCache(context.Background())
+
+	// then
+	assert.NoError(t, err)
+	clientMock.AssertExpectations(t)
+}
+
+func Test_Service_ReloadCache_InvalidModifiedLeavesCacheUntouched(t *testing.T) {
+	// given
+	var nilTime *time.Time
+	latestModified := time.Now()
+
+	clientMock := new(mocks.MarvelDataFetcher)
+	clientMock.On("GetAllCharacters", mock.Anything, nilTime).Return([]*marvel.MarvelApiCharacterData{
+		{
+			Id:       1009282,
+			Name:     "Doctor Strange",
+			Modified: "not a time",
+		},
+	}, nil)
+
+	cachedIds := marvel.NewIntSet()
+	cachedIds.Add(1009351)
+	cache := marvel.NewInMemCache()
+	cache.SetCharacterIds(*cachedIds, latestModified)
+
+	detailCache := marvel.NewInMemDetailCache(marvel.DetailCacheConfig{TTL: time.Minute})
+	detailCache.SetCharacter(&marvel.Character{Id: 1009351, Name: "Hulk"})
+
+	service := marvel.NewService(new(marvel.Config), clientMock, cache, detailCache, logging.Discard())
+
+	// when
+	err := service.ReloadCache(context.Background())
+
+	// then
+	assert.Error(t, err)
+	charIds, cachedLatestModified := cache.GetCharacterIds()
+	assert.Equal(t, []int{1009351}, charIds.ToSlice())
+	assert.True(t, latestModified.Equal(cachedLatestModified))
+	_, hulkCached := detailCache.GetCharacter(1009351)
+	assert.True(t, hulkCached, "removal of the cached character must not be recorded")
+	clientMock.AssertExpectations(t)
+}
+
+func Test_Service_GetAllCharacterIds_BudgetExhaustedServesCache(t *testing.T) {
+	// given
+	latestModified := time.Now()
+
+	clientMock := new(mocks.MarvelDataFetcher)
+	clientMock.On("GetAllCharacters", mock.Anything, &latestModified).Return(nil, errs.NewServiceUnavailable("marvel api call budget exhausted"))
+
+	cachedIds := marvel.NewIntSet()
+	cachedIds.Add(1009351)
+	cache := marvel.NewInMemCache()
+	cache.SetCharacterIds(*cachedIds, latestModified)
+	service := testService(clientMock, cache)
+
+	// when
+	charIds, err := service.GetAllCharacterIds(context.Background())
+
+	// then
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []int{1009351}, charIds)
+	clientMock.AssertExpectations(t)
+}
+
+func Test_Service_GetCharacter_ServedFromDetailCache(t *testing.T) {
+	// given
+	charId := 1009610
+
+	clientMock := new(mocks.MarvelDataFetcher)
+	clientMock.On("GetCharacter", mock.Anything, charId).Return(&marvel.MarvelApiCharacterData{
+		Id:   charId,
+		Name: "Spider-Man",
+	}, nil).Once()
+
+	detailCache := marvel.NewInMemDetailCache(marvel.DetailCacheConfig{TTL: time.Minute})
+	service := marvel.NewService(new(marvel.Config), clientMock, marvel.NewInMemCache(), detailCache, logging.Discard())
+
+	// when
+	first, err1 := service.GetCharacter(context.Background(), charId)
+	second, err2 := service.GetCharacter(context.Background(), charId)
+
+	// then
+	assert.NoError(t, err1)
+	assert.NoError(t, err2)
+	assert.Equal(t, first, second)
+	clientMock.AssertNumberOfCalls(t, "GetCharacter", 1)
+}
+
+func Test_Service_GetCharacter_NotFoundCached(t *testing.T) {
+	// given
+	charId := 9111111
+
+	clientMock := new(mocks.MarvelDataFetcher)
+	clientMock.On("GetCharacter", mock.Anything, charId).Return(nil, errs.NewNotFound("no results")).Once()
+
+	detailCache := marvel.NewInMemDetailCache(marvel.DetailCacheConfig{TTL: time.Minute, NotFoundTTL: time.Minute})
+	service := marvel.NewService(new(marvel.Config), clientMock, marvel.NewInMemCache(), detailCache, logging.Discard())
+
+	// when
+	_, err1 := service.GetCharacter(context.Background(), charId)
+	_, err2 := service.GetCharacter(context.Background(), charId)
+
+	// then
+	assert.IsType(t, new(errs.NotFound), err1)
+	assert.IsType(t, new(errs.NotFound), err2)
+	clientMock.AssertNumberOfCalls(t, "GetCharacter", 1)
+}
+
+func Test_Service_GetAllCharacterIds_ConcurrentCallersShareFetch(t *testing.T) {
+	// given
+	var latestModified *time.Time
+
+	clientMock := new(mocks.MarvelDataFetcher)
+	clientMock.On("GetAllCharacters", mock.Anything, latestModified).Return([]*marvel.MarvelApiCharacterData{
+		{
+			Id:       1009282,
+			Name:     "Doctor Strange",
+			Modified: "2020-07-21T10:33:36-0400",
+		},
+	}, nil).After(50 * time.Millisecond)
+
+	service := testService(clientMock, marvel.NewInMemCache())
+
+	// when
+	var wg sync.WaitGroup
+	results := make([][]int, 10)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], _ = service.GetAllCharacterIds(context.Background())
+		}(i)
+	}
+	wg.Wait()
+
+	// then
+	for _, charIds := range results {
+		assert.Equal(t, []int{1009282}, charIds)
+	}
+	clientMock.AssertNumberOfCalls(t, "GetAllCharacters", 1)
+}
+
+func Test_Service_GetCharacter_ConcurrentCallersShareFetch(t *testing.T) {
+	// given
+	charId := 1009610
+
+	clientMock := new(mocks.MarvelDataFetcher)
+	clientMock.On("GetCharacter", mock.Anything, charId).Return(&marvel.MarvelApiCharacterData{
+		Id:   charId,
+		Name: "Spider-Man",
+	}, nil).After(50 * time.Millisecond)
+
+	service := testService(clientMock, marvel.NewInMemCache())
+
+	// when
+	var wg sync.WaitGroup
+	results := make([]*marvel.Character, 10)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], _ = service.GetCharacter(context.Background(), charId)
+		}(i)
+	}
+	wg.Wait()
+
+	// then
+	for _, char := range results {
+		assert.Equal(t, "Spider-Man", char.Name)
+	}
+	clientMock.AssertNumberOfCalls(t, "GetCharacter", 1)
+}
+
+func Test_Service_GetCharacter_CallerCancelled(t *testing.T) {
+	// given
+	charId := 1009610
+
+	clientMock := new(mocks.MarvelDataFetcher)
+	clientMock.On("GetCharacter", mock.Anything, charId).Return(&marvel.MarvelApiCharacterData{
+		Id:   charId,
+		Name: "Spider-Man",
+	}, nil).After(time.Second)
+
+	service := testService(clientMock, marvel.NewInMemCache())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	// when
+	_, err := service.GetCharacter(ctx, charId)
+
+	// then
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func Test_Service_GetAllCharacterIds_ServedFromCacheAfterSync(t *testing.T) {
+	// given
+	var nilTime *time.Time
+
+	clientMock := new(mocks.MarvelDataFetcher)
+	clientMock.On("GetAllCharacters", mock.Anything, nilTime).Return([]*marvel.MarvelApiCharacterData{
 		{
 			Id:       1009282,
 			Name:     "Doctor Strange",
@@ -124,12 +377,598 @@ func Test_Service_ReloadCache_HappyPath(t *testing.T) {
 		},
 	}, nil)
 
-	service := marvel.NewService(clientMock, marvel.NewInMemCache())
+	service := testService(clientMock, marvel.NewInMemCache())
+	assert.NoError(t, service.ReloadCache(context.Background()))
+
+	// when
+	charIds, err := service.GetAllCharacterIds(context.Background())
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1009282}, charIds)
+	assert.False(t, service.SyncStatus().LastSuccess.IsZero())
+	clientMock.AssertNumberOfCalls(t, "GetAllCharacters", 1)
+}
+
+func Test_Service_SyncCache_AddsModifiedChars(t *testing.T) {
+	// given
+	latestModified, _ := time.Parse(time.RFC3339, "2020-07-21T10:33:36-04:00")
+
+	clientMock := new(mocks.MarvelDataFetcher)
+	clientMock.On("GetAllCharacters", mock.Anything, &latestModified).Return([]*marvel.MarvelApiCharacterData{
+		{
+			Id:       1009187,
+			Name:     "Black Panther",
+			Modified: "2021-02-01T10:00:00-0400",
+		},
+	}, nil)
+
+	cachedIds := marvel.NewIntSet()
+	cachedIds.Add(1009282)
+	cache := marvel.NewInMemCache()
+	cache.SetCharacterIds(*cachedIds, latestModified)
+	service := testService(clientMock, cache)
 
 	// when
-	err := service.ReloadCache()
+	err := service.SyncCache(context.Background())
 
 	// then
 	assert.NoError(t, err)
+	charIds, newLatestModified := cache.GetCharacterIds()
+	assert.ElementsMatch(t, []int{1009282, 1009187}, charIds.ToSlice())
+	assert.Equal(t, "2021-02-01T14:00:00Z", newLatestModified.UTC().Format(time.RFC3339))
+	assert.Equal(t, 1, cachedIds.Len(), "previously cached set must not be modified")
 	clientMock.AssertExpectations(t)
 }
+
+func Test_Service_SyncCache_ModifiedCharsEvictedFromDetailCache(t *testing.T) {
+	// given
+	latestModified, _ := time.Parse(time.RFC3339, "2020-07-21T10:33:36-04:00")
+
+	clientMock := new(mocks.MarvelDataFetcher)
+	clientMock.On("GetAllCharacters", mock.Anything, &latestModified).Return([]*marvel.MarvelApiCharacterData{
+		{
+			Id:       1009187,
+			Name:     "Black Panther",
+			Modified: "2021-02-01T10:00:00-0400",
+		},
+	}, nil)
+
+	cachedIds := marvel.NewIntSet()
+	cachedIds.Add(1009187)
+	cachedIds.Add(1009282)
+	cache := marvel.NewInMemCache()
+	cache.SetCharacterIds(*cachedIds, latestModified)
+
+	detailCache := marvel.NewInMemDetailCache(marvel.DetailCacheConfig{TTL: time.Minute})
+	detailCache.SetCharacter(&marvel.Character{Id: 1009187, Name: "Black Panther", Description: "outdated"})
+	detailCache.SetCharacter(&marvel.Character{Id: 1009282, Name: "Doctor Strange"})
+
+	service := marvel.NewService(new(marvel.Config), clientMock, cache, detailCache, logging.Discard())
+
+	// when
+	err := service.SyncCache(context.Background())
+
+	// then
+	assert.NoError(t, err)
+	_, modifiedCached := detailCache.GetCharacter(1009187)
+	_, unchangedCached := detailCache.GetCharacter(1009282)
+	assert.False(t, modifiedCached)
+	assert.True(t, unchangedCached)
+	clientMock.AssertExpectations(t)
+}
+
+func Test_Service_GetAllCharacterIdsOrdered(t *testing.T) {
+	tests := map[string]struct {
+		orderBy  string
+		expected []int
+	}{
+		"name": {
+			orderBy:  "name",
+			expected: []int{1009187, 1009282, 1009351},
+		},
+		"name descending": {
+			orderBy:  "-name",
+			expected: []int{1009351, 1009282, 1009187},
+		},
+		"modified": {
+			orderBy:  "modified",
+			expected: []int{1009351, 1009187, 1009282},
+		},
+		"modified descending": {
+			orderBy:  "-modified",
+			expected: []int{1009282, 1009187, 1009351},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			// given
+			var nilTime *time.Time
+
+			clientMock := new(mocks.MarvelDataFetcher)
+			clientMock.On("GetAllCharacters", mock.Anything, nilTime).Return([]*marvel.MarvelApiCharacterData{
+				{
+					Id:       1009282,
+					Name:     "Doctor Strange",
+					Modified: "2020-07-21T10:33:36-0400",
+				},
+				{
+					Id:       1009187,
+					Name:     "Black Panther",
+					Modified: "2018-06-19T16:39:46-0400",
+				},
+				{
+					Id:       1009351,
+					Name:     "Hulk",
+					Modified: "2013-09-18T15:54:04-0400",
+				},
+			}, nil)
+
+			service := testService(clientMock, marvel.NewInMemCache())
+
+			// when
+			charIds, err := service.GetAllCharacterIdsOrdered(context.Background(), tc.orderBy)
+
+			// then
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, charIds)
+		})
+	}
+}
+
+func Test_Service_GetAllCharacterIdsOrdered_NotIndexedByEmptySync(t *testing.T) {
+	// given
+	var nilTime *time.Time
+
+	clientMock := new(mocks.MarvelDataFetcher)
+	clientMock.On("GetAllCharacters", mock.Anything, nilTime).Return([]*marvel.MarvelApiCharacterData{}, nil)
+
+	service := testService(clientMock, marvel.NewInMemCache())
+
+	// when
+	charIds, err := service.GetAllCharacterIdsOrdered(context.Background(), "name")
+
+	// then
+	assert.Nil(t, charIds)
+	assert.IsType(t, new(errs.ServiceUnavailable), err)
+}
+
+func Test_Service_GetAllCharacterIdsOrdered_IndexKeptByEmptyReload(t *testing.T) {
+	// given
+	var nilTime *time.Time
+
+	clientMock := new(mocks.MarvelDataFetcher)
+	clientMock.On("GetAllCharacters", mock.Anything, nilTime).Return([]*marvel.MarvelApiCharacterData{
+		{Id: 1009282, Name: "Doctor Strange", Modified: "2020-07-21T10:33:36-0400"},
+		{Id: 1009187, Name: "Black Panther", Modified: "2018-06-19T16:39:46-0400"},
+	}, nil).Once()
+	clientMock.On("GetAllCharacters", mock.Anything, nilTime).Return([]*marvel.MarvelApiCharacterData{}, nil).Once()
+
+	service := testService(clientMock, marvel.NewInMemCache())
+	assert.NoError(t, service.ReloadCache(context.Background()))
+	assert.NoError(t, service.ReloadCache(context.Background()))
+
+	// when
+	charIds, err := service.GetAllCharacterIdsOrdered(context.Background(), "name")
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1009187, 1009282}, charIds)
+	clientMock.AssertExpectations(t)
+}
+
+func Test_Service_GetAllCharacterIds_RecordsCacheLookups(t *testing.T) {
+	// given
+	var nilTime *time.Time
+
+	clientMock := new(mocks.MarvelDataFetcher)
+	clientMock.On("GetAllCharacters", mock.Anything, nilTime).Return([]*marvel.MarvelApiCharacterData{
+		{Id: 1009282, Name: "Doctor Strange", Modified: "2020-07-21T10:33:36-0400"},
+	}, nil).Once()
+
+	service := testService(clientMock, marvel.NewInMemCache())
+
+	hitSeries := `cache_requests_total{cache="ids",result="hit"}`
+	missSeries := `cache_requests_total{cache="ids",result="miss"}`
+
+	// the default registry is shared by every test run in the process, so only the increase is checked
+	before := scrapeMetrics()
+
+	// when
+	_, err1 := service.GetAllCharacterIds(context.Background())
+	_, err2 := service.GetAllCharacterIds(context.Background())
+
+	// then
+	assert.NoError(t, err1)
+	assert.NoError(t, err2)
+	after := scrapeMetrics()
+	assert.Equal(t, 1.0, after[missSeries]-before[missSeries])
+	assert.Equal(t, 1.0, after[hitSeries]-before[hitSeries])
+	clientMock.AssertExpectations(t)
+}
+
+func Test_Service_RegisterMetrics_CacheEntries(t *testing.T) {
+	// given
+	cachedIds := marvel.NewIntSet()
+	cachedIds.Add(1009282)
+	cachedIds.Add(1009187)
+	cache := marvel.NewInMemCache()
+	cache.SetCharacterIds(*cachedIds, time.Now())
+	service := testService(new(mocks.MarvelDataFetcher), cache)
+
+	registry := prometheus.NewRegistry()
+	service.RegisterMetrics(registry)
+
+	rr := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/metrics", nil)
+
+	// when
+	promhttp.HandlerFor(registry, promhttp.HandlerOpts{}).ServeHTTP(rr, req)
+
+	// then
+	assert.Contains(t, rr.Body.String(), `cache_entries{cache="ids"} 2`+"\n")
+	assert.Contains(t, rr.Body.String(), `cache_entries{cache="detail"} 0`+"\n")
+	assert.Contains(t, rr.Body.String(), "cache_last_successful_sync_timestamp_seconds 0\n")
+}
+
+func Test_Service_GetAllCharacterIds_StaleServedWhenSyncFails(t *testing.T) {
+	// given
+	var nilTime *time.Time
+
+	clientMock := new(mocks.MarvelDataFetcher)
+	clientMock.On("GetAllCharacters", mock.Anything, nilTime).Return([]*marvel.MarvelApiCharacterData{
+		{
+			Id:       1009282,
+			Name:     "Doctor Strange",
+			Modified: "2020-07-21T10:33:36-0400",
+		},
+	}, nil)
+	clientMock.On("GetAllCharacters", mock.Anything, mock.Anything).Return(nil, errs.NewBadGateway("error response from marvel api"))
+
+	cfg := &marvel.Config{MaxStaleness: time.Hour}
+	service := marvel.NewService(cfg, clientMock, marvel.NewInMemCache(), marvel.NewInMemDetailCache(marvel.DetailCacheConfig{}), logging.Discard())
+	assert.NoError(t, service.ReloadCache(context.Background()))
+	assert.Error(t, service.SyncCache(context.Background()))
+
+	// when
+	charIds, err := service.GetAllCharacterIds(context.Background())
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1009282}, charIds)
+	assert.True(t, service.SyncStatus().Stale())
+	assert.Equal(t, 1, service.SyncStatus().FailedSyncs)
+}
+
+func Test_Service_GetAllCharacterIds_TooStale(t *testing.T) {
+	// given
+	var nilTime *time.Time
+
+	clientMock := new(mocks.MarvelDataFetcher)
+	clientMock.On("GetAllCharacters", mock.Anything, nilTime).Return([]*marvel.MarvelApiCharacterData{
+		{
+			Id:       1009282,
+			Name:     "Doctor Strange",
+			Modified: "2020-07-21T10:33:36-0400",
+		},
+	}, nil)
+	clientMock.On("GetAllCharacters", mock.Anything, mock.Anything).Return(nil, errs.NewBadGateway("error response from marvel api"))
+
+	cfg := &marvel.Config{MaxStaleness: time.Millisecond}
+	service := marvel.NewService(cfg, clientMock, marvel.NewInMemCache(), marvel.NewInMemDetailCache(marvel.DetailCacheConfig{}), logging.Discard())
+	assert.NoError(t, service.ReloadCache(context.Background()))
+	time.Sleep(5 * time.Millisecond)
+	assert.Error(t, service.SyncCache(context.Background()))
+
+	// when
+	_, err := service.GetAllCharacterIds(context.Background())
+
+	// then
+	assert.Error(t, err)
+	assert.IsType(t, new(errs.BadGateway), err)
+}
+
+func Test_Service_RestoreSnapshot_HappyPath(t *testing.T) {
+	// given
+	var nilTime *time.Time
+
+	clientMock := new(mocks.MarvelDataFetcher)
+	clientMock.On("GetAllCharacters", mock.Anything, nilTime).Return([]*marvel.MarvelApiCharacterData{
+		{
+			Id:       1009282,
+			Name:     "Doctor Strange",
+			Modified: "2020-07-21T10:33:36-0400",
+		},
+		{
+			Id:       1009187,
+			Name:     "Black Panther",
+			Modified: "2018-06-19T16:39:46-0400",
+		},
+	}, nil)
+	clientMock.On("GetCharacter", mock.Anything, 1009282).Return(&marvel.MarvelApiCharacterData{
+		Id:   1009282,
+		Name: "Doctor Strange",
+	}, nil)
+
+	cfg := &marvel.Config{SnapshotPath: filepath.Join(t.TempDir(), "snapshot.json")}
+	detailCacheCfg := marvel.DetailCacheConfig{TTL: time.Minute}
+
+	service := marvel.NewService(cfg, clientMock, marvel.NewInMemCache(), marvel.NewInMemDetailCache(detailCacheCfg), logging.Discard())
+	assert.NoError(t, service.ReloadCache(context.Background()))
+	_, err := service.GetCharacter(context.Background(), 1009282)
+	assert.NoError(t, err)
+	assert.NoError(t, service.SaveSnapshot())
+
+	restoredCache := marvel.NewInMemCache()
+	restoredDetailCache := marvel.NewInMemDetailCache(detailCacheCfg)
+	restoredService := marvel.NewService(cfg, new(mocks.MarvelDataFetcher), restoredCache, restoredDetailCache, logging.Discard())
+
+	// when
+	err = restoredService.RestoreSnapshot()
+
+	// then
+	assert.NoError(t, err)
+	charIds, latestModified := restoredCache.GetCharacterIds()
+	assert.ElementsMatch(t, []int{1009282, 1009187}, charIds.ToSlice())
+	assert.Equal(t, "2020-07-21T14:33:36Z", latestModified.UTC().Format(time.RFC3339))
+	char, cached := restoredDetailCache.GetCharacter(1009282)
+	assert.True(t, cached)
+	assert.Equal(t, "Doctor Strange", char.Name)
+	assert.Equal(t, service.SyncStatus().LastSuccess.Unix(), restoredService.SyncStatus().LastSuccess.Unix())
+}
+
+func Test_Service_RestoreSnapshot_ExpiredCharactersSkipped(t *testing.T) {
+	// given
+	clientMock := new(mocks.MarvelDataFetcher)
+	clientMock.On("GetCharacter", mock.Anything, 1009282).Return(&marvel.MarvelApiCharacterData{
+		Id:   1009282,
+		Name: "Doctor Strange",
+	}, nil)
+
+	cfg := &marvel.Config{SnapshotPath: filepath.Join(t.TempDir(), "snapshot.json")}
+
+	service := marvel.NewService(cfg, clientMock, marvel.NewInMemCache(), marvel.NewInMemDetailCache(marvel.DetailCacheConfig{TTL: 50 * time.Millisecond}), logging.Discard())
+	_, err := service.GetCharacter(context.Background(), 1009282)
+	assert.NoError(t, err)
+	assert.NoError(t, service.SaveSnapshot())
+
+	time.Sleep(60 * time.Millisecond)
+
+	restoredDetailCache := marvel.NewInMemDetailCache(marvel.DetailCacheConfig{TTL: time.Hour})
+	restoredService := marvel.NewService(cfg, new(mocks.MarvelDataFetcher), marvel.NewInMemCache(), restoredDetailCache, logging.Discard())
+
+	// when
+	err = restoredService.RestoreSnapshot()
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, 0, restoredDetailCache.Len())
+}
+
+func Test_Service_RestoreSnapshot_Corrupt(t *testing.T) {
+	// given
+	path := filepath.Join(t.TempDir(), "snapshot.json")
+	assert.NoError(t, ioutil.WriteFile(path, []byte(`{"version": 1, "characterIds": [1009`), 0644))
+
+	cache := marvel.NewInMemCache()
+	service := marvel.NewService(&marvel.Config{SnapshotPath: path}, new(mocks.MarvelDataFetcher), cache, marvel.NewInMemDetailCache(marvel.DetailCacheConfig{}), logging.Discard())
+
+	// when
+	err := service.RestoreSnapshot()
+
+	// then
+	assert.Error(t, err)
+	charIds, _ := cache.GetCharacterIds()
+	assert.Equal(t, 0, charIds.Len())
+}
+
+func Test_Service_RestoreSnapshot_VersionMismatch(t *testing.T) {
+	// given
+	path := filepath.Join(t.TempDir(), "snapshot.json")
+	assert.NoError(t, ioutil.WriteFile(path, []byte(`{"version": 999, "characterIds": [1009282]}`), 0644))
+
+	cache := marvel.NewInMemCache()
+	service := marvel.NewService(&marvel.Config{SnapshotPath: path}, new(mocks.MarvelDataFetcher), cache, marvel.NewInMemDetailCache(marvel.DetailCacheConfig{}), logging.Discard())
+
+	// when
+	err := service.RestoreSnapshot()
+
+	// then
+	assert.Error(t, err)
+	charIds, _ := cache.GetCharacterIds()
+	assert.Equal(t, 0, charIds.Len())
+}
+
+func Test_Service_ReconcileCache_RemovesDeletedChars(t *testing.T) {
+	// given
+	var nilTime *time.Time
+
+	clientMock := new(mocks.MarvelDataFetcher)
+	clientMock.On("GetAllCharacters", mock.Anything, nilTime).Return([]*marvel.MarvelApiCharacterData{
+		{Id: 1009282, Modified: "2020-07-21T10:33:36-0400"},
+		{Id: 1009187, Modified: "2018-06-19T16:39:46-0400"},
+		{Id: 1009610, Modified: "2018-06-19T16:39:46-0400"},
+	}, nil)
+
+	cachedIds := marvel.NewIntSet()
+	cachedIds.Add(1009282)
+	cachedIds.Add(1009187)
+	cachedIds.Add(1011001)
+	cache := marvel.NewInMemCache()
+	cache.SetCharacterIds(*cachedIds, time.Now())
+	service := marvel.NewService(&marvel.Config{ReconcileMinRatio: 0.9}, clientMock, cache, marvel.NewInMemDetailCache(marvel.DetailCacheConfig{}), logging.Discard())
+
+	// when
+	result, err := service.ReconcileCache(context.Background())
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.Added)
+	assert.Equal(t, 1, result.Removed)
+	assert.False(t, result.Skipped)
+	charIds, _ := cache.GetCharacterIds()
+	assert.ElementsMatch(t, []int{1009282, 1009187, 1009610}, charIds.ToSlice())
+	assert.Equal(t, result, service.SyncStatus().LastReconcile)
+}
+
+func Test_Service_ReconcileCache_SkippedWhenTooFewChars(t *testing.T) {
+	// given
+	var nilTime *time.Time
+
+	clientMock := new(mocks.MarvelDataFetcher)
+	clientMock.On("GetAllCharacters", mock.Anything, nilTime).Return([]*marvel.MarvelApiCharacterData{
+		{Id: 1009282, Modified: "2020-07-21T10:33:36-0400"},
+	}, nil)
+
+	cachedIds := marvel.NewIntSet()
+	cachedIds.Add(1009282)
+	cachedIds.Add(1009187)
+	cachedIds.Add(1011001)
+	cache := marvel.NewInMemCache()
+	cache.SetCharacterIds(*cachedIds, time.Now())
+	service := marvel.NewService(&marvel.Config{ReconcileMinRatio: 0.9}, clientMock, cache, marvel.NewInMemDetailCache(marvel.DetailCacheConfig{}), logging.Discard())
+
+	// when
+	result, err := service.ReconcileCache(context.Background())
+
+	// then
+	assert.NoError(t, err)
+	assert.True(t, result.Skipped)
+	assert.Equal(t, 2, result.Removed)
+	charIds, _ := cache.GetCharacterIds()
+	assert.ElementsMatch(t, []int{1009282, 1009187, 1011001}, charIds.ToSlice())
+	assert.True(t, service.SyncStatus().LastSuccess.IsZero(), "a skipped reconciliation is not a successful sync")
+}
+
+func Test_Service_SearchCharacters_FromNameIndex(t *testing.T) {
+	// given
+	var nilTime *time.Time
+
+	clientMock := new(mocks.MarvelDataFetcher)
+	clientMock.On("GetAllCharacters", mock.Anything, nilTime).Return([]*marvel.MarvelApiCharacterData{
+		{Id: 1009610, Name: "Spider-Man", Modified: "2020-07-21T10:30:10-0400"},
+		{Id: 1009609, Name: "Spider-Girl (May Parker)", Modified: "2014-04-29T14:18:17-0400"},
+		{Id: 1009608, Name: "Spider-dok", Modified: "2014-04-29T14:18:17-0400"},
+		{Id: 1009351, Name: "Hulk", Modified: "2014-04-29T14:18:17-0400"},
+	}, nil)
+
+	service := testService(clientMock, marvel.NewInMemCache())
+	assert.NoError(t, service.ReloadCache(context.Background()))
+
+	// when
+	byName, err1 := service.SearchCharacters(context.Background(), marvel.CharacterSearch{Name: "HULK", Limit: 20})
+	byPrefix, err2 := service.SearchCharacters(context.Background(), marvel.CharacterSearch{NameStartsWith: "spider-", Limit: 2, Offset: 1})
+
+	// then
+	assert.NoError(t, err1)
+	assert.Equal(t, 1, byName.Total)
+	assert.Equal(t, "Hulk", byName.Characters[0].Name)
+
+	assert.NoError(t, err2)
+	assert.Equal(t, 3, byPrefix.Total)
+	assert.Len(t, byPrefix.Characters, 2)
+	assert.Equal(t, "Spider-Girl (May Parker)", byPrefix.Characters[0].Name)
+	assert.Equal(t, "Spider-Man", byPrefix.Characters[1].Name)
+
+	clientMock.AssertNotCalled(t, "SearchCharacters", mock.Anything, mock.Anything)
+}
+
+func Test_Service_SearchCharacters_FromMarvelApi(t *testing.T) {
+	// given
+	search := marvel.CharacterSearch{Name: "Hulk", Limit: 20}
+
+	clientMock := new(mocks.MarvelDataFetcher)
+	clientMock.On("SearchCharacters", mock.Anything, search).Return([]*marvel.MarvelApiCharacterData{
+		{Id: 1009351, Name: "Hulk"},
+	}, 1, nil)
+
+	service := testService(clientMock, marvel.NewInMemCache())
+
+	// when
+	charPage, err := service.SearchCharacters(context.Background(), search)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, 1, charPage.Total)
+	assert.Equal(t, []*marvel.Character{{Id: 1009351, Name: "Hulk"}}, charPage.Characters)
+	clientMock.AssertExpectations(t)
+}
+
+func Test_Service_Search_IndexUpdatedOnSync(t *testing.T) {
+	// given
+	var nilTime *time.Time
+	latestModified, _ := time.Parse("2006-01-02T15:04:05-0700", "2020-07-21T10:30:10-0400")
+
+	clientMock := new(mocks.MarvelDataFetcher)
+	clientMock.On("GetAllCharacters", mock.Anything, nilTime).Return([]*marvel.MarvelApiCharacterData{
+		{Id: 1009610, Name: "Spider-Man", Modified: "2020-07-21T10:30:10-0400"},
+		{Id: 1009718, Name: "Wolverine", Modified: "2014-04-29T14:18:17-0400"},
+	}, nil).Once()
+	clientMock.On("GetAllCharacters", mock.Anything, &latestModified).Return([]*marvel.MarvelApiCharacterData{
+		{Id: 1009351, Name: "Hulk", Description: "Caught in a gamma bomb explosion.", Modified: "2021-01-05T11:00:00-0500"},
+	}, nil).Once()
+
+	service := testService(clientMock, marvel.NewInMemCache())
+
+	// when
+	beforeSync, err1 := service.Search(context.Background(), "spiderman", 20, 0)
+	syncErr := service.SyncCache(context.Background())
+	afterSync, err2 := service.Search(context.Background(), "gamma", 20, 0)
+
+	// then
+	assert.NoError(t, err1)
+	assert.Equal(t, 1, beforeSync.Total)
+	assert.Equal(t, "Spider-Man", beforeSync.Results[0].Name)
+
+	assert.NoError(t, syncErr)
+	assert.NoError(t, err2)
+	assert.Equal(t, 1, afterSync.Total)
+	assert.Equal(t, "Hulk", afterSync.Results[0].Name)
+	clientMock.AssertExpectations(t)
+}
+
+func Test_Service_GetCharacters_PartialSuccess(t *testing.T) {
+	// given
+	clientMock := new(mocks.MarvelDataFetcher)
+	clientMock.On("GetCharacter", mock.Anything, 1009351).Return(&marvel.MarvelApiCharacterData{Id: 1009351, Name: "Hulk"}, nil).Once()
+	clientMock.On("GetCharacter", mock.Anything, 1).Return(nil, errs.NewNotFound("no results")).Once()
+	clientMock.On("GetCharacter", mock.Anything, 2).Return(nil, errs.NewBadGateway("unexpected response from Marvel's API")).Once()
+	clientMock.On("GetCharacter", mock.Anything, 1009610).Return(&marvel.MarvelApiCharacterData{Id: 1009610, Name: "Spider-Man"}, nil).Once()
+
+	cfg := &marvel.Config{BatchConcurrency: 2}
+	detailCache := marvel.NewInMemDetailCache(marvel.DetailCacheConfig{TTL: time.Minute, NotFoundTTL: time.Minute})
+	service := marvel.NewService(cfg, clientMock, marvel.NewInMemCache(), detailCache, logging.Discard())
+
+	// when
+	batch, err := service.GetCharacters(context.Background(), []int{1009351, 1, 2, 1009610, 1009351})
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, []*marvel.Character{{Id: 1009351, Name: "Hulk"}, {Id: 1009610, Name: "Spider-Man"}}, batch.Characters)
+	assert.Equal(t, []*marvel.BatchError{
+		{Id: 1, Status: http.StatusNotFound, Error: "no results"},
+		{Id: 2, Status: http.StatusBadGateway, Error: "unexpected response from Marvel's API"},
+	}, batch.Errors)
+	_, cached := detailCache.GetCharacter(1009610)
+	assert.True(t, cached)
+	clientMock.AssertExpectations(t)
+}
+
+// scrapeMetrics returns the value of every series of the default registry.
+func scrapeMetrics() map[string]float64 {
+	rr := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/metrics", nil)
+	promhttp.Handler().ServeHTTP(rr, req)
+
+	values := make(map[string]float64)
+	for _, line := range strings.Split(rr.Body.String(), "\n") {
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		i := strings.LastIndex(line, " ")
+		v, _ := strconv.ParseFloat(line[i+1:], 64)
+		values[line[:i]] = v
+	}
+	return values
+}
@@ -32,6 +32,14 @@ func (is *IntSet) Contains(i int) bool {
 	return exists
 }
 
+func (is *IntSet) Copy() *IntSet {
+	elems := make(map[int]void, len(is.elems))
+	for i := range is.elems {
+		elems[i] = member
+	}
+	return &IntSet{elems}
+}
+
 func (is *IntSet) ToSlice() []int {
 	list := []int{}
 	for i := range is.elems {
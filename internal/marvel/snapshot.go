@@ -0,0 +1,110 @@
+package marvel
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// snapshotVersion is bumped whenever the snapshot format changes,
+// so that snapshots written by older versions are discarded.
+const snapshotVersion = 6
+
+// cacheSnapshot is the on-disk representation of the cached character data.
+type cacheSnapshot struct {
+	Version        int                `json:"version"`
+	SavedAt        time.Time          `json:"savedAt"`
+	LastSynced     time.Time          `json:"lastSynced"`
+	LatestModified time.Time          `json:"latestModified"`
+	CharacterIds   []int              `json:"characterIds"`
+	Characters     []*CachedCharacter `json:"characters"`
+	NameIndex      []*Character       `json:"nameIndex"`
+	NameIndexFull  bool               `json:"nameIndexFull"`
+	Graph          *graphSnapshot     `json:"graph"`
+	ChangeLog      *changeLogSnapshot `json:"changeLog"`
+}
+
+// graphSnapshot is the on-disk representation of the co-appearance graph.
+type graphSnapshot struct {
+	LatestModified time.Time     `json:"latestModified"`
+	Comics         map[int][]int `json:"comics"`
+}
+
+// changeLogSnapshot is the on-disk representation of the change log.
+type changeLogSnapshot struct {
+	Epoch      int64             `json:"epoch"`
+	Horizon    time.Time         `json:"horizon"`
+	LastSeq    int64             `json:"lastSeq"`
+	EvictedSeq int64             `json:"evictedSeq"`
+	Changes    []*changeSnapshot `json:"changes"`
+}
+
+type changeSnapshot struct {
+	Seq int64 `json:"seq"`
+	Change
+}
+
+// snapshotStore persists cache snapshots to a file.
+type snapshotStore struct {
+	path string
+}
+
+func newSnapshotStore(path string) *snapshotStore {
+	return &snapshotStore{path}
+}
+
+// save writes the snapshot atomically, by writing to a temporary file
+// first and then renaming it over the previous snapshot.
+func (s *snapshotStore) save(snapshot *cacheSnapshot) error {
+	dir := filepath.Dir(s.path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(dir, filepath.Base(s.path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), s.path)
+}
+
+// load reads the snapshot, failing if it is missing, corrupt or of a different version.
+func (s *snapshotStore) load() (*cacheSnapshot, error) {
+	data, err := ioutil.ReadFile(s.path)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := new(cacheSnapshot)
+	if err := json.Unmarshal(data, snapshot); err != nil {
+		return nil, fmt.Errorf("corrupt cache snapshot: %w", err)
+	}
+
+	if snapshot.Version != snapshotVersion {
+		return nil, fmt.Errorf("cache snapshot version %d does not match expected version %d", snapshot.Version, snapshotVersion)
+	}
+
+	return snapshot, nil
+}
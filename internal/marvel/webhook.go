@@ -0,0 +1,574 @@
+package marvel
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gkatanacio/marvel-characters-api/internal/errs"
+	"github.com/gkatanacio/marvel-characters-api/internal/logging"
+)
+
+// WebhookConfig configures the webhooks notified of the changes to the characters.
+type WebhookConfig struct {
+	// Urls are webhooks notified in addition to those registered through the admin API.
+	Urls []string `envconfig:"URLS"`
+	// Secret is the key the payloads sent to the webhooks from Urls are signed with.
+	Secret string `envconfig:"SECRET"`
+	// AdminToken is the bearer token required by the admin API. Empty disables the admin API.
+	AdminToken string `envconfig:"ADMIN_TOKEN"`
+	// MaxAttempts is the total number of attempts per delivery (including the first one).
+	// Values below 1 are treated as 1, i.e. no retries.
+	MaxAttempts int `envconfig:"MAX_ATTEMPTS" default:"5"`
+	// BaseDelay is the delay before the first retry. It is doubled on every subsequent retry.
+	BaseDelay time.Duration `envconfig:"BASE_DELAY" default:"1s"`
+	// MaxDelay caps the delay between attempts.
+	MaxDelay time.Duration `envconfig:"MAX_DELAY" default:"1m"`
+	// Timeout is the time limit of each attempt. Zero means no limit.
+	Timeout time.Duration `envconfig:"TIMEOUT" default:"10s"`
+	// QueueSize is the number of deliveries waiting to be sent to a webhook after which
+	// new deliveries fail right away. Values below 1 are treated as 1.
+	QueueSize int `envconfig:"QUEUE_SIZE" default:"100"`
+	// HistorySize is the number of most recent deliveries kept per webhook. Zero means no limit.
+	HistorySize int `envconfig:"HISTORY_SIZE" default:"50"`
+	// DeadLetterSize is the number of most recent failed deliveries kept. Zero means no limit.
+	DeadLetterSize int `envconfig:"DEAD_LETTER_SIZE" default:"100"`
+}
+
+// WebhookEventCharactersChanged is the event of the payloads describing the changes found by a sync.
+const WebhookEventCharactersChanged = "characters.changed"
+
+// WebhookRegistration describes a webhook to register.
+type WebhookRegistration struct {
+	Url string `json:"url"`
+	// Secret is the key the payloads are signed with. A random one is generated if empty.
+	Secret string `json:"secret,omitempty"`
+}
+
+// Webhook is an endpoint notified of the changes to the characters.
+type Webhook struct {
+	Id  string `json:"id"`
+	Url string `json:"url"`
+	// Secret is only returned when the webhook is registered
+	Secret string `json:"secret,omitempty"`
+	// FromConfig is true for the webhooks configured through environment variables, which cannot be unregistered
+	FromConfig bool      `json:"fromConfig"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+// WebhookPayload is the JSON body sent to the webhooks.
+type WebhookPayload struct {
+	Event string `json:"event"`
+	// SyncedAt is when the sync that found the changes was made
+	SyncedAt time.Time `json:"syncedAt"`
+	Changes  []*Change `json:"changes"`
+}
+
+// DeliveryStatus is the state of the delivery of a payload to a webhook.
+type DeliveryStatus string
+
+const (
+	DeliveryPending   DeliveryStatus = "pending"
+	DeliveryDelivered DeliveryStatus = "delivered"
+	DeliveryFailed    DeliveryStatus = "failed"
+)
+
+// WebhookDelivery is the delivery of a payload to a webhook, along with the attempts made.
+type WebhookDelivery struct {
+	Id        string             `json:"id"`
+	WebhookId string             `json:"webhookId"`
+	Url       string             `json:"url"`
+	Status    DeliveryStatus     `json:"status"`
+	Payload   *WebhookPayload    `json:"payload"`
+	Attempts  []*DeliveryAttempt `json:"attempts"`
+}
+
+// DeliveryAttempt is an attempt to deliver a payload to a webhook.
+type DeliveryAttempt struct {
+	Time time.Time `json:"time"`
+	// StatusCode is the status of the response of the webhook (unset if no response was received)
+	StatusCode int    `json:"statusCode,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// WebhookManager is the interface for administering the webhooks.
+type WebhookManager interface {
+	Webhooks() []*Webhook
+	Register(reg WebhookRegistration) (*Webhook, error)
+	Unregister(id string) error
+	Deliveries(webhookId string) ([]*WebhookDelivery, error)
+	DeadLetters() []*WebhookDelivery
+	RetryDeadLetter(deliveryId string) error
+}
+
+// ChangeSubscriber is the interface for subscribing to the changes to the characters.
+type ChangeSubscriber interface {
+	SubscribeChanges(lastEventId string) (*ChangeSubscription, error)
+}
+
+// WebhookDispatcher sends the changes found by each sync of the cache to the registered webhooks,
+// as HMAC-signed JSON payloads. Failed deliveries are retried with exponential backoff, and kept
+// in a dead-letter list once all attempts fail.
+type WebhookDispatcher struct {
+	sync.Mutex
+	cfg         WebhookConfig
+	httpClient  *http.Client
+	rnd         *lockedRand
+	workers     []*webhookWorker // in registration order
+	deadLetters []*WebhookDelivery
+	logger      *logging.Logger
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+// webhookWorker sends the deliveries of a webhook one at a time, in order.
+type webhookWorker struct {
+	webhook *Webhook
+	queue   chan *WebhookDelivery
+	// history holds the most recent deliveries, oldest first
+	history []*WebhookDelivery
+	// unregistered is closed when the webhook is unregistered
+	unregistered chan struct{}
+}
+
+func NewWebhookDispatcher(cfg WebhookConfig, logger *logging.Logger) *WebhookDispatcher {
+	d := &WebhookDispatcher{
+		cfg:        cfg,
+		httpClient: &http.Client{Timeout: cfg.Timeout},
+		rnd:        newLockedRand(),
+		logger:     logger,
+		stop:       make(chan struct{}),
+	}
+
+	for _, u := range cfg.Urls {
+		d.add(&Webhook{Id: newRandomHex(8), Url: u, Secret: cfg.Secret, FromConfig: true, CreatedAt: time.Now()})
+	}
+
+	return d
+}
+
+// Start sends the changes from `source` to the webhooks in the background, until Stop is called.
+func (d *WebhookDispatcher) Start(source ChangeSubscriber) {
+	d.wg.Add(1)
+	go d.run(source)
+}
+
+// Stop stops sending changes to the webhooks. Deliveries still being retried are given up on.
+func (d *WebhookDispatcher) Stop() {
+	close(d.stop)
+	d.wg.Wait()
+}
+
+// Webhooks returns the registered webhooks (without their secrets).
+func (d *WebhookDispatcher) Webhooks() []*Webhook {
+	d.Lock()
+	defer d.Unlock()
+
+	webhooks := make([]*Webhook, 0, len(d.workers))
+	for _, w := range d.workers {
+		webhookCopy := *w.webhook
+		webhookCopy.Secret = ""
+		webhooks = append(webhooks, &webhookCopy)
+	}
+	return webhooks
+}
+
+// Register adds a webhook, which is notified of the changes found from now on.
+func (d *WebhookDispatcher) Register(reg WebhookRegistration) (*Webhook, error) {
+	u, err := url.Parse(reg.Url)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, errs.NewBadRequest("url must be an absolute http or https URL")
+	}
+
+	secret := reg.Secret
+	if secret == "" {
+		secret = newRandomHex(32)
+	}
+
+	webhook := &Webhook{Id: newRandomHex(8), Url: reg.Url, Secret: secret, CreatedAt: time.Now()}
+	d.add(webhook)
+
+	webhookCopy := *webhook
+	return &webhookCopy, nil
+}
+
+// Unregister removes the webhook with the given ID. Its pending deliveries are dropped.
+func (d *WebhookDispatcher) Unregister(id string) error {
+	d.Lock()
+	defer d.Unlock()
+
+	for i, w := range d.workers {
+		if w.webhook.Id != id {
+			continue
+		}
+		if w.webhook.FromConfig {
+			return errs.NewBadRequest("webhooks from the configuration cannot be unregistered")
+		}
+		d.workers = append(d.workers[:i:i], d.workers[i+1:]...)
+		close(w.unregistered)
+		return nil
+	}
+
+	return errs.NewNotFound("webhook not found")
+}
+
+// Deliveries returns the most recent deliveries to the webhook with the given ID, newest first.
+func (d *WebhookDispatcher) Deliveries(webhookId string) ([]*WebhookDelivery, error) {
+	d.Lock()
+	defer d.Unlock()
+
+	w := d.worker(webhookId)
+	if w == nil {
+		return nil, errs.NewNotFound("webhook not found")
+	}
+
+	deliveries := make([]*WebhookDelivery, 0, len(w.history))
+	for i := len(w.history) - 1; i >= 0; i-- {
+		deliveries = append(deliveries, copyDelivery(w.history[i]))
+	}
+	return deliveries, nil
+}
+
+// DeadLetters returns the most recent deliveries whose attempts all failed, newest first.
+func (d *WebhookDispatcher) DeadLetters() []*WebhookDelivery {
+	d.Lock()
+	defer d.Unlock()
+
+	deliveries := make([]*WebhookDelivery, 0, len(d.deadLetters))
+	for i := len(d.deadLetters) - 1; i >= 0; i-- {
+		deliveries = append(deliveries, copyDelivery(d.deadLetters[i]))
+	}
+	return deliveries
+}
+
+// RetryDeadLetter takes the delivery with the given ID off the dead-letter list and attempts it again.
+func (d *WebhookDispatcher) RetryDeadLetter(deliveryId string) error {
+	d.Lock()
+	defer d.Unlock()
+
+	for i, delivery := range d.deadLetters {
+		if delivery.Id != deliveryId {
+			continue
+		}
+
+		w := d.worker(delivery.WebhookId)
+		if w == nil {
+			return errs.NewNotFound("webhook no longer registered")
+		}
+
+		d.deadLetters = append(d.deadLetters[:i:i], d.deadLetters[i+1:]...)
+		delivery.Status = DeliveryPending
+		d.enqueue(w, delivery)
+		return nil
+	}
+
+	return errs.NewNotFound("dead letter not found")
+}
+
+func (d *WebhookDispatcher) add(webhook *Webhook) {
+	queueSize := d.cfg.QueueSize
+	if queueSize < 1 {
+		queueSize = 1
+	}
+
+	w := &webhookWorker{
+		webhook:      webhook,
+		queue:        make(chan *WebhookDelivery, queueSize),
+		unregistered: make(chan struct{}),
+	}
+
+	d.Lock()
+	d.workers = append(d.workers, w)
+	d.Unlock()
+
+	d.wg.Add(1)
+	go d.work(w)
+}
+
+func (d *WebhookDispatcher) worker(webhookId string) *webhookWorker {
+	for _, w := range d.workers {
+		if w.webhook.Id == webhookId {
+			return w
+		}
+	}
+	return nil
+}
+
+// run forwards the changes from `source` to the webhooks. If the subscription is dropped, it
+// subscribes again from the last change forwarded, so no change is missed.
+func (d *WebhookDispatcher) run(source ChangeSubscriber) {
+	defer d.wg.Done()
+
+	var cursor string
+	for {
+		sub, err := source.SubscribeChanges(cursor)
+		if err != nil {
+			d.logger.Error("webhooks failed to subscribe to changes, missing those since the last one sent", "error", err)
+			cursor = ""
+			select {
+			case <-d.stop:
+				return
+			case <-time.After(time.Second):
+			}
+			continue
+		}
+
+		var stopped bool
+		cursor, stopped = d.forward(sub, cursor)
+		sub.Close()
+		if stopped {
+			return
+		}
+	}
+}
+
+// forward sends the changes of the subscription to the webhooks, until the subscription is closed
+// or the dispatcher is stopped. It returns the ID of the last change sent.
+func (d *WebhookDispatcher) forward(sub *ChangeSubscription, cursor string) (string, bool) {
+	cursor = d.notify(sub.Backlog, cursor)
+
+	for {
+		select {
+		case <-d.stop:
+			return cursor, true
+		case e, ok := <-sub.Events:
+			if !ok {
+				return cursor, false
+			}
+
+			// the changes found by a sync are all sent at once, so they are waiting to be received together
+			events := []*ChangeEvent{e}
+		drain:
+			for {
+				select {
+				case e, ok := <-sub.Events:
+					if !ok {
+						break drain
+					}
+					events = append(events, e)
+				default:
+					break drain
+				}
+			}
+
+			cursor = d.notify(events, cursor)
+		}
+	}
+}
+
+// notify queues a delivery to every webhook for the changes found by each sync among `events`.
+// It returns the ID of the last event, or `cursor` if there are none.
+func (d *WebhookDispatcher) notify(events []*ChangeEvent, cursor string) string {
+	if len(events) == 0 {
+		return cursor
+	}
+
+	d.Lock()
+	defer d.Unlock()
+
+	for start := 0; start < len(events); {
+		syncedAt := events[start].Change.Time
+		end := start
+		var changes []*Change
+		for end < len(events) && events[end].Change.Time.Equal(syncedAt) {
+			changes = append(changes, events[end].Change)
+			end++
+		}
+
+		payload := &WebhookPayload{Event: WebhookEventCharactersChanged, SyncedAt: syncedAt, Changes: changes}
+		for _, w := range d.workers {
+			delivery := &WebhookDelivery{
+				Id:        newRandomHex(8),
+				WebhookId: w.webhook.Id,
+				Url:       w.webhook.Url,
+				Status:    DeliveryPending,
+				Payload:   payload,
+			}
+			d.addToHistory(w, delivery)
+			d.enqueue(w, delivery)
+		}
+
+		start = end
+	}
+
+	return events[len(events)-1].Id
+}
+
+// addToHistory adds a new delivery to the most recent deliveries to the webhook.
+// It must be called with the dispatcher locked.
+func (d *WebhookDispatcher) addToHistory(w *webhookWorker, delivery *WebhookDelivery) {
+	w.history = append(w.history, delivery)
+	if d.cfg.HistorySize > 0 && len(w.history) > d.cfg.HistorySize {
+		w.history = append([]*WebhookDelivery(nil), w.history[len(w.history)-d.cfg.HistorySize:]...)
+	}
+}
+
+// enqueue queues the delivery to the webhook, failing it right away if the queue is full.
+// It must be called with the dispatcher locked.
+func (d *WebhookDispatcher) enqueue(w *webhookWorker, delivery *WebhookDelivery) {
+	select {
+	case w.queue <- delivery:
+	default:
+		delivery.Attempts = append(delivery.Attempts, &DeliveryAttempt{Time: time.Now(), Error: "delivery queue full"})
+		d.fail(delivery)
+	}
+}
+
+// fail marks the delivery as failed and adds it to the dead-letter list.
+// It must be called with the dispatcher locked.
+func (d *WebhookDispatcher) fail(delivery *WebhookDelivery) {
+	d.logger.Warn("webhook delivery failed", "delivery", delivery.Id, "webhook", delivery.WebhookId, "attempts", len(delivery.Attempts))
+
+	delivery.Status = DeliveryFailed
+	d.deadLetters = append(d.deadLetters, delivery)
+	if d.cfg.DeadLetterSize > 0 && len(d.deadLetters) > d.cfg.DeadLetterSize {
+		d.deadLetters = append([]*WebhookDelivery(nil), d.deadLetters[len(d.deadLetters)-d.cfg.DeadLetterSize:]...)
+	}
+}
+
+func (d *WebhookDispatcher) work(w *webhookWorker) {
+	defer d.wg.Done()
+
+	for {
+		select {
+		case <-d.stop:
+			return
+		case <-w.unregistered:
+			return
+		case delivery := <-w.queue:
+			d.deliver(w, delivery)
+		}
+	}
+}
+
+// deliver attempts the delivery until it succeeds or the attempts run out.
+func (d *WebhookDispatcher) deliver(w *webhookWorker, delivery *WebhookDelivery) {
+	body, err := json.Marshal(delivery.Payload)
+	if err != nil {
+		d.logger.Error("failed to encode webhook payload", "delivery", delivery.Id, "error", err)
+		return
+	}
+
+	maxAttempts := d.cfg.MaxAttempts
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+	backoff := RetryPolicy{BaseDelay: d.cfg.BaseDelay, MaxDelay: d.cfg.MaxDelay, Jitter: 0.5}
+
+	for attempt := 1; ; attempt++ {
+		a := d.attempt(w.webhook, delivery.Id, body)
+
+		d.Lock()
+		delivery.Attempts = append(delivery.Attempts, a)
+		if a.Error == "" {
+			delivery.Status = DeliveryDelivered
+			d.Unlock()
+			return
+		}
+		if attempt >= maxAttempts {
+			d.fail(delivery)
+			d.Unlock()
+			return
+		}
+		d.Unlock()
+
+		select {
+		case <-time.After(backoff.backoff(attempt, d.rnd)):
+		case <-w.unregistered:
+			return
+		case <-d.stop:
+			d.Lock()
+			d.fail(delivery)
+			d.Unlock()
+			return
+		}
+	}
+}
+
+// attempt posts the payload to the webhook once.
+func (d *WebhookDispatcher) attempt(webhook *Webhook, deliveryId string, body []byte) *DeliveryAttempt {
+	a := &DeliveryAttempt{Time: time.Now()}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-d.stop:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.Url, bytes.NewReader(body))
+	if err != nil {
+		a.Error = attemptError(err)
+		return a
+	}
+
+	timestamp := strconv.FormatInt(a.Time.Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Webhook-Id", deliveryId)
+	req.Header.Set("X-Webhook-Timestamp", timestamp)
+	req.Header.Set("X-Webhook-Signature", SignWebhookPayload(webhook.Secret, timestamp, body))
+
+	resp, err := d.httpClient.Do(req)
+	if err != nil {
+		a.Error = attemptError(err)
+		return a
+	}
+	defer resp.Body.Close()
+	io.Copy(ioutil.Discard, resp.Body)
+
+	a.StatusCode = resp.StatusCode
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		a.Error = "unexpected response status " + resp.Status
+	}
+	return a
+}
+
+// attemptError describes the error of a failed attempt without the credentials
+// the URL of the webhook may hold, as attempts are listed by the admin endpoints.
+func attemptError(err error) string {
+	if urlErr, ok := err.(*url.Error); ok {
+		urlErr.URL = logging.RedactUrl(urlErr.URL)
+	}
+	return err.Error()
+}
+
+// SignWebhookPayload computes the signature sent in the X-Webhook-Signature header: the hex-encoded
+// HMAC-SHA256 of the timestamp (from the X-Webhook-Timestamp header), a dot and the body, keyed
+// with the secret of the webhook, prefixed with "sha256=".
+func SignWebhookPayload(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func copyDelivery(delivery *WebhookDelivery) *WebhookDelivery {
+	deliveryCopy := *delivery
+	deliveryCopy.Attempts = append([]*DeliveryAttempt(nil), delivery.Attempts...)
+	return &deliveryCopy
+}
+
+// newRandomHex returns `n` random bytes, hex-encoded.
+func newRandomHex(n int) string {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,211 @@
+package marvel_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gkatanacio/marvel-characters-api/internal/errs"
+	"github.com/gkatanacio/marvel-characters-api/internal/logging"
+	"github.com/gkatanacio/marvel-characters-api/internal/marvel"
+	"github.com/stretchr/testify/assert"
+)
+
+// changeLogSource subscribes to a change log, signalling each subscription.
+type changeLogSource struct {
+	*marvel.ChangeLog
+	subscribed chan struct{}
+}
+
+func (s *changeLogSource) SubscribeChanges(lastEventId string) (*marvel.ChangeSubscription, error) {
+	defer func() { s.subscribed <- struct{}{} }()
+	return s.Subscribe(lastEventId, 10)
+}
+
+// testReceiver records the webhook requests it receives, answering them with the given statuses in turn
+// (and the last one once they run out).
+type testReceiver struct {
+	sync.Mutex
+	statuses []int
+	requests []*http.Request
+	bodies   [][]byte
+}
+
+func (rc *testReceiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rc.Lock()
+	defer rc.Unlock()
+
+	body, _ := ioutil.ReadAll(r.Body)
+	rc.requests = append(rc.requests, r)
+	rc.bodies = append(rc.bodies, body)
+
+	status := rc.statuses[len(rc.statuses)-1]
+	if len(rc.requests) <= len(rc.statuses) {
+		status = rc.statuses[len(rc.requests)-1]
+	}
+	w.WriteHeader(status)
+}
+
+func (rc *testReceiver) received() int {
+	rc.Lock()
+	defer rc.Unlock()
+	return len(rc.requests)
+}
+
+func startWebhooks(t *testing.T, cfg marvel.WebhookConfig) (*marvel.WebhookDispatcher, *marvel.ChangeLog) {
+	changeLog := marvel.NewChangeLog(0)
+	changeLog.Record(nil, time.Now())
+
+	source := &changeLogSource{ChangeLog: changeLog, subscribed: make(chan struct{}, 1)}
+	webhooks := marvel.NewWebhookDispatcher(cfg, logging.Discard())
+	webhooks.Start(source)
+	t.Cleanup(webhooks.Stop)
+	<-source.subscribed
+
+	return webhooks, changeLog
+}
+
+func Test_WebhookDispatcher_SignedPayloadDelivered(t *testing.T) {
+	// given
+	receiver := &testReceiver{statuses: []int{http.StatusOK}}
+	ts := httptest.NewServer(receiver)
+	defer ts.Close()
+
+	webhooks, changeLog := startWebhooks(t, marvel.WebhookConfig{Urls: []string{ts.URL}, Secret: "s3cr3t"})
+	syncedAt := time.Date(2021, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	// when
+	changeLog.Record([]*marvel.Change{
+		{Id: 1009610, Type: marvel.ChangeAdded},
+		{Id: 1009351, Type: marvel.ChangeRemoved},
+	}, syncedAt)
+
+	// then
+	assert.Eventually(t, func() bool { return receiver.received() == 1 }, time.Second, time.Millisecond)
+
+	receiver.Lock()
+	req, body := receiver.requests[0], receiver.bodies[0]
+	receiver.Unlock()
+	assert.Equal(t, "application/json", req.Header.Get("Content-Type"))
+	assert.Equal(t, marvel.SignWebhookPayload("s3cr3t", req.Header.Get("X-Webhook-Timestamp"), body), req.Header.Get("X-Webhook-Signature"))
+
+	var payload marvel.WebhookPayload
+	assert.NoError(t, json.Unmarshal(body, &payload))
+	assert.Equal(t, marvel.WebhookEventCharactersChanged, payload.Event)
+	assert.True(t, syncedAt.Equal(payload.SyncedAt))
+	assert.Len(t, payload.Changes, 2)
+
+	webhookId := webhooks.Webhooks()[0].Id
+	assert.Eventually(t, func() bool {
+		deliveries, _ := webhooks.Deliveries(webhookId)
+		return len(deliveries) == 1 && deliveries[0].Status == marvel.DeliveryDelivered
+	}, time.Second, time.Millisecond)
+	deliveries, _ := webhooks.Deliveries(webhookId)
+	assert.Equal(t, req.Header.Get("X-Webhook-Id"), deliveries[0].Id)
+}
+
+func Test_WebhookDispatcher_RetriedWithBackoff(t *testing.T) {
+	// given
+	receiver := &testReceiver{statuses: []int{http.StatusInternalServerError, http.StatusServiceUnavailable, http.StatusOK}}
+	ts := httptest.NewServer(receiver)
+	defer ts.Close()
+
+	webhooks, changeLog := startWebhooks(t, marvel.WebhookConfig{MaxAttempts: 3, BaseDelay: time.Millisecond})
+	webhook, err := webhooks.Register(marvel.WebhookRegistration{Url: ts.URL})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, webhook.Secret)
+
+	// when
+	changeLog.Record([]*marvel.Change{{Id: 1009610, Type: marvel.ChangeAdded}}, time.Now())
+
+	// then
+	assert.Eventually(t, func() bool {
+		deliveries, _ := webhooks.Deliveries(webhook.Id)
+		return len(deliveries) == 1 && deliveries[0].Status == marvel.DeliveryDelivered
+	}, time.Second, time.Millisecond)
+	deliveries, _ := webhooks.Deliveries(webhook.Id)
+	assert.Len(t, deliveries[0].Attempts, 3)
+	assert.Equal(t, http.StatusInternalServerError, deliveries[0].Attempts[0].StatusCode)
+	assert.NotEmpty(t, deliveries[0].Attempts[0].Error)
+	assert.Empty(t, deliveries[0].Attempts[2].Error)
+	assert.Empty(t, webhooks.DeadLetters())
+}
+
+func Test_WebhookDispatcher_DeadLetterRetried(t *testing.T) {
+	// given
+	receiver := &testReceiver{statuses: []int{http.StatusInternalServerError, http.StatusInternalServerError, http.StatusNoContent}}
+	ts := httptest.NewServer(receiver)
+	defer ts.Close()
+
+	webhooks, changeLog := startWebhooks(t, marvel.WebhookConfig{MaxAttempts: 2, BaseDelay: time.Millisecond})
+	webhook, err := webhooks.Register(marvel.WebhookRegistration{Url: ts.URL, Secret: "s3cr3t"})
+	assert.NoError(t, err)
+
+	changeLog.Record([]*marvel.Change{{Id: 1009610, Type: marvel.ChangeAdded}}, time.Now())
+	assert.Eventually(t, func() bool { return len(webhooks.DeadLetters()) == 1 }, time.Second, time.Millisecond)
+	deadLetter := webhooks.DeadLetters()[0]
+	assert.Equal(t, marvel.DeliveryFailed, deadLetter.Status)
+	assert.Len(t, deadLetter.Attempts, 2)
+
+	// when
+	err = webhooks.RetryDeadLetter(deadLetter.Id)
+
+	// then
+	assert.NoError(t, err)
+	assert.Empty(t, webhooks.DeadLetters())
+	assert.Eventually(t, func() bool {
+		deliveries, _ := webhooks.Deliveries(webhook.Id)
+		return deliveries[0].Status == marvel.DeliveryDelivered
+	}, time.Second, time.Millisecond)
+	deliveries, _ := webhooks.Deliveries(webhook.Id)
+	assert.Len(t, deliveries, 1, "the retried delivery must not be listed twice")
+	assert.IsType(t, &errs.NotFound{}, webhooks.RetryDeadLetter(deadLetter.Id))
+}
+
+func Test_WebhookDispatcher_AttemptErrorRedacted(t *testing.T) {
+	// given
+	ts := httptest.NewServer(http.NotFoundHandler())
+	ts.Close() // attempts fail to connect
+
+	webhooks, changeLog := startWebhooks(t, marvel.WebhookConfig{MaxAttempts: 1})
+	_, err := webhooks.Register(marvel.WebhookRegistration{Url: ts.URL + "/hook?token=t0k3n"})
+	assert.NoError(t, err)
+
+	// when
+	changeLog.Record([]*marvel.Change{{Id: 1009610, Type: marvel.ChangeAdded}}, time.Now())
+
+	// then
+	assert.Eventually(t, func() bool { return len(webhooks.DeadLetters()) == 1 }, time.Second, time.Millisecond)
+	attemptErr := webhooks.DeadLetters()[0].Attempts[0].Error
+	assert.Contains(t, attemptErr, "token=REDACTED")
+	assert.NotContains(t, attemptErr, "t0k3n")
+}
+
+func Test_WebhookDispatcher_Admin(t *testing.T) {
+	// given
+	webhooks := marvel.NewWebhookDispatcher(marvel.WebhookConfig{Urls: []string{"http://localhost:9999/hook"}, Secret: "s3cr3t"}, logging.Discard())
+	defer webhooks.Stop()
+
+	// when
+	_, invalidErr := webhooks.Register(marvel.WebhookRegistration{Url: "ftp://localhost/hook"})
+	registered, err := webhooks.Register(marvel.WebhookRegistration{Url: "https://example.com/hook"})
+
+	// then
+	assert.IsType(t, &errs.BadRequest{}, invalidErr)
+	assert.NoError(t, err)
+
+	listed := webhooks.Webhooks()
+	assert.Len(t, listed, 2)
+	assert.True(t, listed[0].FromConfig)
+	assert.Empty(t, listed[0].Secret)
+	assert.Equal(t, registered.Id, listed[1].Id)
+
+	assert.IsType(t, &errs.BadRequest{}, webhooks.Unregister(listed[0].Id))
+	assert.NoError(t, webhooks.Unregister(registered.Id))
+	assert.IsType(t, &errs.NotFound{}, webhooks.Unregister(registered.Id))
+	assert.Len(t, webhooks.Webhooks(), 1)
+}
@@ -3,6 +3,8 @@
 package mocks
 
 import (
+	context "context"
+
 	marvel "github.com/gkatanacio/marvel-characters-api/internal/marvel"
 	mock "github.com/stretchr/testify/mock"
 
@@ -14,13 +16,13 @@ type MarvelDataFetcher struct {
 	mock.Mock
 }
 
-// GetAllCharacters provides a mock function with given fields: modifiedSince
-func (_m *MarvelDataFetcher) GetAllCharacters(modifiedSince *time.Time) ([]*marvel.MarvelApiCharacterData, error) {
-	ret := _m.Called(modifiedSince)
+// GetAllCharacters provides a mock function with given fields: ctx, modifiedSince
+func (_m *MarvelDataFetcher) GetAllCharacters(ctx context.Context, modifiedSince *time.Time) ([]*marvel.MarvelApiCharacterData, error) {
+	ret := _m.Called(ctx, modifiedSince)
 
 	var r0 []*marvel.MarvelApiCharacterData
-	if rf, ok := ret.Get(0).(func(*time.Time) []*marvel.MarvelApiCharacterData); ok {
-		r0 = rf(modifiedSince)
+	if rf, ok := ret.Get(0).(func(context.Context, *time.Time) []*marvel.MarvelApiCharacterData); ok {
+		r0 = rf(ctx, modifiedSince)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]*marvel.MarvelApiCharacterData)
@@ -28,8 +30,8 @@ func (_m *MarvelDataFetcher) GetAllCharacters(modifiedSince *time.Time) ([]*marv
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(*time.Time) error); ok {
-		r1 = rf(modifiedSince)
+	if rf, ok := ret.Get(1).(func(context.Context, *time.Time) error); ok {
+		r1 = rf(ctx, modifiedSince)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -37,13 +39,13 @@ func (_m *MarvelDataFetcher) GetAllCharacters(modifiedSince *time.Time) ([]*marv
 	return r0, r1
 }
 
-// GetCharacter provides a mock function with given fields: id
-func (_m *MarvelDataFetcher) GetCharacter(id int) (*marvel.MarvelApiCharacterData, error) {
-	ret := _m.Called(id)
+// GetCharacter provides a mock function with given fields: ctx, id
+func (_m *MarvelDataFetcher) GetCharacter(ctx context.Context, id int) (*marvel.MarvelApiCharacterData, error) {
+	ret := _m.Called(ctx, id)
 
 	var r0 *marvel.MarvelApiCharacterData
-	if rf, ok := ret.Get(0).(func(int) *marvel.MarvelApiCharacterData); ok {
-		r0 = rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, int) *marvel.MarvelApiCharacterData); ok {
+		r0 = rf(ctx, id)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*marvel.MarvelApiCharacterData)
@@ -51,11 +53,456 @@ func (_m *MarvelDataFetcher) GetCharacter(id int) (*marvel.MarvelApiCharacterDat
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(int) error); ok {
-		r1 = rf(id)
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetCharacterComics provides a mock function with given fields: ctx, id, query
+func (_m *MarvelDataFetcher) GetCharacterComics(ctx context.Context, id int, query marvel.ResourceQuery) ([]*marvel.MarvelApiComicData, int, error) {
+	ret := _m.Called(ctx, id, query)
+
+	var r0 []*marvel.MarvelApiComicData
+	if rf, ok := ret.Get(0).(func(context.Context, int, marvel.ResourceQuery) []*marvel.MarvelApiComicData); ok {
+		r0 = rf(ctx, id, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*marvel.MarvelApiComicData)
+		}
+	}
+
+	var r1 int
+	if rf, ok := ret.Get(1).(func(context.Context, int, marvel.ResourceQuery) int); ok {
+		r1 = rf(ctx, id, query)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, int, marvel.ResourceQuery) error); ok {
+		r2 = rf(ctx, id, query)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetCharacterEvents provides a mock function with given fields: ctx, id, query
+func (_m *MarvelDataFetcher) GetCharacterEvents(ctx context.Context, id int, query marvel.ResourceQuery) ([]*marvel.MarvelApiEventData, int, error) {
+	ret := _m.Called(ctx, id, query)
+
+	var r0 []*marvel.MarvelApiEventData
+	if rf, ok := ret.Get(0).(func(context.Context, int, marvel.ResourceQuery) []*marvel.MarvelApiEventData); ok {
+		r0 = rf(ctx, id, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*marvel.MarvelApiEventData)
+		}
+	}
+
+	var r1 int
+	if rf, ok := ret.Get(1).(func(context.Context, int, marvel.ResourceQuery) int); ok {
+		r1 = rf(ctx, id, query)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, int, marvel.ResourceQuery) error); ok {
+		r2 = rf(ctx, id, query)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetCharacterSeries provides a mock function with given fields: ctx, id, query
+func (_m *MarvelDataFetcher) GetCharacterSeries(ctx context.Context, id int, query marvel.ResourceQuery) ([]*marvel.MarvelApiSeriesData, int, error) {
+	ret := _m.Called(ctx, id, query)
+
+	var r0 []*marvel.MarvelApiSeriesData
+	if rf, ok := ret.Get(0).(func(context.Context, int, marvel.ResourceQuery) []*marvel.MarvelApiSeriesData); ok {
+		r0 = rf(ctx, id, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*marvel.MarvelApiSeriesData)
+		}
+	}
+
+	var r1 int
+	if rf, ok := ret.Get(1).(func(context.Context, int, marvel.ResourceQuery) int); ok {
+		r1 = rf(ctx, id, query)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, int, marvel.ResourceQuery) error); ok {
+		r2 = rf(ctx, id, query)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetCharacterStories provides a mock function with given fields: ctx, id, query
+func (_m *MarvelDataFetcher) GetCharacterStories(ctx context.Context, id int, query marvel.ResourceQuery) ([]*marvel.MarvelApiStoryData, int, error) {
+	ret := _m.Called(ctx, id, query)
+
+	var r0 []*marvel.MarvelApiStoryData
+	if rf, ok := ret.Get(0).(func(context.Context, int, marvel.ResourceQuery) []*marvel.MarvelApiStoryData); ok {
+		r0 = rf(ctx, id, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*marvel.MarvelApiStoryData)
+		}
+	}
+
+	var r1 int
+	if rf, ok := ret.Get(1).(func(context.Context, int, marvel.ResourceQuery) int); ok {
+		r1 = rf(ctx, id, query)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, int, marvel.ResourceQuery) error); ok {
+		r2 = rf(ctx, id, query)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetComic provides a mock function with given fields: ctx, id
+func (_m *MarvelDataFetcher) GetComic(ctx context.Context, id int) (*marvel.MarvelApiComicData, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *marvel.MarvelApiComicData
+	if rf, ok := ret.Get(0).(func(context.Context, int) *marvel.MarvelApiComicData); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*marvel.MarvelApiComicData)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
 	} else {
 		r1 = ret.Error(1)
 	}
 
 	return r0, r1
 }
+
+// GetComicCharactersPage provides a mock function with given fields: ctx, modifiedSince, offset
+func (_m *MarvelDataFetcher) GetComicCharactersPage(ctx context.Context, modifiedSince *time.Time, offset int) ([]*marvel.ComicCharacters, int, error) {
+	ret := _m.Called(ctx, modifiedSince, offset)
+
+	var r0 []*marvel.ComicCharacters
+	if rf, ok := ret.Get(0).(func(context.Context, *time.Time, int) []*marvel.ComicCharacters); ok {
+		r0 = rf(ctx, modifiedSince, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*marvel.ComicCharacters)
+		}
+	}
+
+	var r1 int
+	if rf, ok := ret.Get(1).(func(context.Context, *time.Time, int) int); ok {
+		r1 = rf(ctx, modifiedSince, offset)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, *time.Time, int) error); ok {
+		r2 = rf(ctx, modifiedSince, offset)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetComics provides a mock function with given fields: ctx, query
+func (_m *MarvelDataFetcher) GetComics(ctx context.Context, query marvel.ResourceQuery) ([]*marvel.MarvelApiComicData, int, error) {
+	ret := _m.Called(ctx, query)
+
+	var r0 []*marvel.MarvelApiComicData
+	if rf, ok := ret.Get(0).(func(context.Context, marvel.ResourceQuery) []*marvel.MarvelApiComicData); ok {
+		r0 = rf(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*marvel.MarvelApiComicData)
+		}
+	}
+
+	var r1 int
+	if rf, ok := ret.Get(1).(func(context.Context, marvel.ResourceQuery) int); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, marvel.ResourceQuery) error); ok {
+		r2 = rf(ctx, query)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetCreator provides a mock function with given fields: ctx, id
+func (_m *MarvelDataFetcher) GetCreator(ctx context.Context, id int) (*marvel.MarvelApiCreatorData, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *marvel.MarvelApiCreatorData
+	if rf, ok := ret.Get(0).(func(context.Context, int) *marvel.MarvelApiCreatorData); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*marvel.MarvelApiCreatorData)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetCreators provides a mock function with given fields: ctx, query
+func (_m *MarvelDataFetcher) GetCreators(ctx context.Context, query marvel.ResourceQuery) ([]*marvel.MarvelApiCreatorData, int, error) {
+	ret := _m.Called(ctx, query)
+
+	var r0 []*marvel.MarvelApiCreatorData
+	if rf, ok := ret.Get(0).(func(context.Context, marvel.ResourceQuery) []*marvel.MarvelApiCreatorData); ok {
+		r0 = rf(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*marvel.MarvelApiCreatorData)
+		}
+	}
+
+	var r1 int
+	if rf, ok := ret.Get(1).(func(context.Context, marvel.ResourceQuery) int); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, marvel.ResourceQuery) error); ok {
+		r2 = rf(ctx, query)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetEvent provides a mock function with given fields: ctx, id
+func (_m *MarvelDataFetcher) GetEvent(ctx context.Context, id int) (*marvel.MarvelApiEventData, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *marvel.MarvelApiEventData
+	if rf, ok := ret.Get(0).(func(context.Context, int) *marvel.MarvelApiEventData); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*marvel.MarvelApiEventData)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetEvents provides a mock function with given fields: ctx, query
+func (_m *MarvelDataFetcher) GetEvents(ctx context.Context, query marvel.ResourceQuery) ([]*marvel.MarvelApiEventData, int, error) {
+	ret := _m.Called(ctx, query)
+
+	var r0 []*marvel.MarvelApiEventData
+	if rf, ok := ret.Get(0).(func(context.Context, marvel.ResourceQuery) []*marvel.MarvelApiEventData); ok {
+		r0 = rf(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*marvel.MarvelApiEventData)
+		}
+	}
+
+	var r1 int
+	if rf, ok := ret.Get(1).(func(context.Context, marvel.ResourceQuery) int); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, marvel.ResourceQuery) error); ok {
+		r2 = rf(ctx, query)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetSeries provides a mock function with given fields: ctx, id
+func (_m *MarvelDataFetcher) GetSeries(ctx context.Context, id int) (*marvel.MarvelApiSeriesData, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *marvel.MarvelApiSeriesData
+	if rf, ok := ret.Get(0).(func(context.Context, int) *marvel.MarvelApiSeriesData); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*marvel.MarvelApiSeriesData)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSeriesList provides a mock function with given fields: ctx, query
+func (_m *MarvelDataFetcher) GetSeriesList(ctx context.Context, query marvel.ResourceQuery) ([]*marvel.MarvelApiSeriesData, int, error) {
+	ret := _m.Called(ctx, query)
+
+	var r0 []*marvel.MarvelApiSeriesData
+	if rf, ok := ret.Get(0).(func(context.Context, marvel.ResourceQuery) []*marvel.MarvelApiSeriesData); ok {
+		r0 = rf(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*marvel.MarvelApiSeriesData)
+		}
+	}
+
+	var r1 int
+	if rf, ok := ret.Get(1).(func(context.Context, marvel.ResourceQuery) int); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, marvel.ResourceQuery) error); ok {
+		r2 = rf(ctx, query)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetStories provides a mock function with given fields: ctx, query
+func (_m *MarvelDataFetcher) GetStories(ctx context.Context, query marvel.ResourceQuery) ([]*marvel.MarvelApiStoryData, int, error) {
+	ret := _m.Called(ctx, query)
+
+	var r0 []*marvel.MarvelApiStoryData
+	if rf, ok := ret.Get(0).(func(context.Context, marvel.ResourceQuery) []*marvel.MarvelApiStoryData); ok {
+		r0 = rf(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*marvel.MarvelApiStoryData)
+		}
+	}
+
+	var r1 int
+	if rf, ok := ret.Get(1).(func(context.Context, marvel.ResourceQuery) int); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, marvel.ResourceQuery) error); ok {
+		r2 = rf(ctx, query)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetStory provides a mock function with given fields: ctx, id
+func (_m *MarvelDataFetcher) GetStory(ctx context.Context, id int) (*marvel.MarvelApiStoryData, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *marvel.MarvelApiStoryData
+	if rf, ok := ret.Get(0).(func(context.Context, int) *marvel.MarvelApiStoryData); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*marvel.MarvelApiStoryData)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SearchCharacters provides a mock function with given fields: ctx, search
+func (_m *MarvelDataFetcher) SearchCharacters(ctx context.Context, search marvel.CharacterSearch) ([]*marvel.MarvelApiCharacterData, int, error) {
+	ret := _m.Called(ctx, search)
+
+	var r0 []*marvel.MarvelApiCharacterData
+	if rf, ok := ret.Get(0).(func(context.Context, marvel.CharacterSearch) []*marvel.MarvelApiCharacterData); ok {
+		r0 = rf(ctx, search)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*marvel.MarvelApiCharacterData)
+		}
+	}
+
+	var r1 int
+	if rf, ok := ret.Get(1).(func(context.Context, marvel.CharacterSearch) int); ok {
+		r1 = rf(ctx, search)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, marvel.CharacterSearch) error); ok {
+		r2 = rf(ctx, search)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
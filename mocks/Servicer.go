@@ -3,6 +3,8 @@
 package mocks
 
 import (
+	context "context"
+
 	marvel "github.com/gkatanacio/marvel-characters-api/internal/marvel"
 	mock "github.com/stretchr/testify/mock"
 )
@@ -12,13 +14,13 @@ type Servicer struct {
 	mock.Mock
 }
 
-// GetAllCharacterIds provides a mock function with given fields:
-func (_m *Servicer) GetAllCharacterIds() ([]int, error) {
-	ret := _m.Called()
+// GetAllCharacterIds provides a mock function with given fields: ctx
+func (_m *Servicer) GetAllCharacterIds(ctx context.Context) ([]int, error) {
+	ret := _m.Called(ctx)
 
 	var r0 []int
-	if rf, ok := ret.Get(0).(func() []int); ok {
-		r0 = rf()
+	if rf, ok := ret.Get(0).(func(context.Context) []int); ok {
+		r0 = rf(ctx)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]int)
@@ -26,8 +28,8 @@ func (_m *Servicer) GetAllCharacterIds() ([]int, error) {
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func() error); ok {
-		r1 = rf()
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -35,13 +37,59 @@ func (_m *Servicer) GetAllCharacterIds() ([]int, error) {
 	return r0, r1
 }
 
-// GetCharacter provides a mock function with given fields: id
-func (_m *Servicer) GetCharacter(id int) (*marvel.Character, error) {
-	ret := _m.Called(id)
+// GetAllCharacterIdsOrdered provides a mock function with given fields: ctx, orderBy
+func (_m *Servicer) GetAllCharacterIdsOrdered(ctx context.Context, orderBy string) ([]int, error) {
+	ret := _m.Called(ctx, orderBy)
+
+	var r0 []int
+	if rf, ok := ret.Get(0).(func(context.Context, string) []int); ok {
+		r0 = rf(ctx, orderBy)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]int)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, orderBy)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetChanges provides a mock function with given fields: ctx, query
+func (_m *Servicer) GetChanges(ctx context.Context, query marvel.ChangeQuery) (*marvel.ChangePage, error) {
+	ret := _m.Called(ctx, query)
+
+	var r0 *marvel.ChangePage
+	if rf, ok := ret.Get(0).(func(context.Context, marvel.ChangeQuery) *marvel.ChangePage); ok {
+		r0 = rf(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*marvel.ChangePage)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, marvel.ChangeQuery) error); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetCharacter provides a mock function with given fields: ctx, id
+func (_m *Servicer) GetCharacter(ctx context.Context, id int) (*marvel.Character, error) {
+	ret := _m.Called(ctx, id)
 
 	var r0 *marvel.Character
-	if rf, ok := ret.Get(0).(func(int) *marvel.Character); ok {
-		r0 = rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, int) *marvel.Character); ok {
+		r0 = rf(ctx, id)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*marvel.Character)
@@ -49,8 +97,8 @@ func (_m *Servicer) GetCharacter(id int) (*marvel.Character, error) {
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(int) error); ok {
-		r1 = rf(id)
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -58,16 +106,611 @@ func (_m *Servicer) GetCharacter(id int) (*marvel.Character, error) {
 	return r0, r1
 }
 
-// ReloadCache provides a mock function with given fields:
-func (_m *Servicer) ReloadCache() error {
-	ret := _m.Called()
+// GetCharacterComics provides a mock function with given fields: ctx, id, query
+func (_m *Servicer) GetCharacterComics(ctx context.Context, id int, query marvel.ResourceQuery) ([]*marvel.Comic, int, error) {
+	ret := _m.Called(ctx, id, query)
+
+	var r0 []*marvel.Comic
+	if rf, ok := ret.Get(0).(func(context.Context, int, marvel.ResourceQuery) []*marvel.Comic); ok {
+		r0 = rf(ctx, id, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*marvel.Comic)
+		}
+	}
+
+	var r1 int
+	if rf, ok := ret.Get(1).(func(context.Context, int, marvel.ResourceQuery) int); ok {
+		r1 = rf(ctx, id, query)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, int, marvel.ResourceQuery) error); ok {
+		r2 = rf(ctx, id, query)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetCharacterEvents provides a mock function with given fields: ctx, id, query
+func (_m *Servicer) GetCharacterEvents(ctx context.Context, id int, query marvel.ResourceQuery) ([]*marvel.Event, int, error) {
+	ret := _m.Called(ctx, id, query)
+
+	var r0 []*marvel.Event
+	if rf, ok := ret.Get(0).(func(context.Context, int, marvel.ResourceQuery) []*marvel.Event); ok {
+		r0 = rf(ctx, id, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*marvel.Event)
+		}
+	}
+
+	var r1 int
+	if rf, ok := ret.Get(1).(func(context.Context, int, marvel.ResourceQuery) int); ok {
+		r1 = rf(ctx, id, query)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, int, marvel.ResourceQuery) error); ok {
+		r2 = rf(ctx, id, query)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetCharacterSeries provides a mock function with given fields: ctx, id, query
+func (_m *Servicer) GetCharacterSeries(ctx context.Context, id int, query marvel.ResourceQuery) ([]*marvel.Series, int, error) {
+	ret := _m.Called(ctx, id, query)
+
+	var r0 []*marvel.Series
+	if rf, ok := ret.Get(0).(func(context.Context, int, marvel.ResourceQuery) []*marvel.Series); ok {
+		r0 = rf(ctx, id, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*marvel.Series)
+		}
+	}
+
+	var r1 int
+	if rf, ok := ret.Get(1).(func(context.Context, int, marvel.ResourceQuery) int); ok {
+		r1 = rf(ctx, id, query)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, int, marvel.ResourceQuery) error); ok {
+		r2 = rf(ctx, id, query)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetCharacterStories provides a mock function with given fields: ctx, id, query
+func (_m *Servicer) GetCharacterStories(ctx context.Context, id int, query marvel.ResourceQuery) ([]*marvel.Story, int, error) {
+	ret := _m.Called(ctx, id, query)
+
+	var r0 []*marvel.Story
+	if rf, ok := ret.Get(0).(func(context.Context, int, marvel.ResourceQuery) []*marvel.Story); ok {
+		r0 = rf(ctx, id, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*marvel.Story)
+		}
+	}
+
+	var r1 int
+	if rf, ok := ret.Get(1).(func(context.Context, int, marvel.ResourceQuery) int); ok {
+		r1 = rf(ctx, id, query)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, int, marvel.ResourceQuery) error); ok {
+		r2 = rf(ctx, id, query)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetCharacters provides a mock function with given fields: ctx, ids
+func (_m *Servicer) GetCharacters(ctx context.Context, ids []int) (*marvel.CharacterBatch, error) {
+	ret := _m.Called(ctx, ids)
+
+	var r0 *marvel.CharacterBatch
+	if rf, ok := ret.Get(0).(func(context.Context, []int) *marvel.CharacterBatch); ok {
+		r0 = rf(ctx, ids)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*marvel.CharacterBatch)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, []int) error); ok {
+		r1 = rf(ctx, ids)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetComic provides a mock function with given fields: ctx, id
+func (_m *Servicer) GetComic(ctx context.Context, id int) (*marvel.Comic, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *marvel.Comic
+	if rf, ok := ret.Get(0).(func(context.Context, int) *marvel.Comic); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*marvel.Comic)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetComics provides a mock function with given fields: ctx, query
+func (_m *Servicer) GetComics(ctx context.Context, query marvel.ResourceQuery) ([]*marvel.Comic, int, error) {
+	ret := _m.Called(ctx, query)
+
+	var r0 []*marvel.Comic
+	if rf, ok := ret.Get(0).(func(context.Context, marvel.ResourceQuery) []*marvel.Comic); ok {
+		r0 = rf(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*marvel.Comic)
+		}
+	}
+
+	var r1 int
+	if rf, ok := ret.Get(1).(func(context.Context, marvel.ResourceQuery) int); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, marvel.ResourceQuery) error); ok {
+		r2 = rf(ctx, query)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetConnections provides a mock function with given fields: ctx, id, query
+func (_m *Servicer) GetConnections(ctx context.Context, id int, query marvel.ResourceQuery) ([]*marvel.Connection, int, error) {
+	ret := _m.Called(ctx, id, query)
+
+	var r0 []*marvel.Connection
+	if rf, ok := ret.Get(0).(func(context.Context, int, marvel.ResourceQuery) []*marvel.Connection); ok {
+		r0 = rf(ctx, id, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*marvel.Connection)
+		}
+	}
+
+	var r1 int
+	if rf, ok := ret.Get(1).(func(context.Context, int, marvel.ResourceQuery) int); ok {
+		r1 = rf(ctx, id, query)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, int, marvel.ResourceQuery) error); ok {
+		r2 = rf(ctx, id, query)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetCreator provides a mock function with given fields: ctx, id
+func (_m *Servicer) GetCreator(ctx context.Context, id int) (*marvel.Creator, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *marvel.Creator
+	if rf, ok := ret.Get(0).(func(context.Context, int) *marvel.Creator); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*marvel.Creator)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetCreators provides a mock function with given fields: ctx, query
+func (_m *Servicer) GetCreators(ctx context.Context, query marvel.ResourceQuery) ([]*marvel.Creator, int, error) {
+	ret := _m.Called(ctx, query)
+
+	var r0 []*marvel.Creator
+	if rf, ok := ret.Get(0).(func(context.Context, marvel.ResourceQuery) []*marvel.Creator); ok {
+		r0 = rf(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*marvel.Creator)
+		}
+	}
+
+	var r1 int
+	if rf, ok := ret.Get(1).(func(context.Context, marvel.ResourceQuery) int); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, marvel.ResourceQuery) error); ok {
+		r2 = rf(ctx, query)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetEvent provides a mock function with given fields: ctx, id
+func (_m *Servicer) GetEvent(ctx context.Context, id int) (*marvel.Event, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *marvel.Event
+	if rf, ok := ret.Get(0).(func(context.Context, int) *marvel.Event); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*marvel.Event)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetEvents provides a mock function with given fields: ctx, query
+func (_m *Servicer) GetEvents(ctx context.Context, query marvel.ResourceQuery) ([]*marvel.Event, int, error) {
+	ret := _m.Called(ctx, query)
+
+	var r0 []*marvel.Event
+	if rf, ok := ret.Get(0).(func(context.Context, marvel.ResourceQuery) []*marvel.Event); ok {
+		r0 = rf(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*marvel.Event)
+		}
+	}
+
+	var r1 int
+	if rf, ok := ret.Get(1).(func(context.Context, marvel.ResourceQuery) int); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, marvel.ResourceQuery) error); ok {
+		r2 = rf(ctx, query)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetPath provides a mock function with given fields: ctx, from, to
+func (_m *Servicer) GetPath(ctx context.Context, from int, to int) ([]*marvel.Connection, error) {
+	ret := _m.Called(ctx, from, to)
+
+	var r0 []*marvel.Connection
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) []*marvel.Connection); ok {
+		r0 = rf(ctx, from, to)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*marvel.Connection)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(ctx, from, to)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSeries provides a mock function with given fields: ctx, id
+func (_m *Servicer) GetSeries(ctx context.Context, id int) (*marvel.Series, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *marvel.Series
+	if rf, ok := ret.Get(0).(func(context.Context, int) *marvel.Series); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*marvel.Series)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSeriesList provides a mock function with given fields: ctx, query
+func (_m *Servicer) GetSeriesList(ctx context.Context, query marvel.ResourceQuery) ([]*marvel.Series, int, error) {
+	ret := _m.Called(ctx, query)
+
+	var r0 []*marvel.Series
+	if rf, ok := ret.Get(0).(func(context.Context, marvel.ResourceQuery) []*marvel.Series); ok {
+		r0 = rf(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*marvel.Series)
+		}
+	}
+
+	var r1 int
+	if rf, ok := ret.Get(1).(func(context.Context, marvel.ResourceQuery) int); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, marvel.ResourceQuery) error); ok {
+		r2 = rf(ctx, query)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetStories provides a mock function with given fields: ctx, query
+func (_m *Servicer) GetStories(ctx context.Context, query marvel.ResourceQuery) ([]*marvel.Story, int, error) {
+	ret := _m.Called(ctx, query)
+
+	var r0 []*marvel.Story
+	if rf, ok := ret.Get(0).(func(context.Context, marvel.ResourceQuery) []*marvel.Story); ok {
+		r0 = rf(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*marvel.Story)
+		}
+	}
+
+	var r1 int
+	if rf, ok := ret.Get(1).(func(context.Context, marvel.ResourceQuery) int); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, marvel.ResourceQuery) error); ok {
+		r2 = rf(ctx, query)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetStory provides a mock function with given fields: ctx, id
+func (_m *Servicer) GetStory(ctx context.Context, id int) (*marvel.Story, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *marvel.Story
+	if rf, ok := ret.Get(0).(func(context.Context, int) *marvel.Story); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*marvel.Story)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReconcileCache provides a mock function with given fields: ctx
+func (_m *Servicer) ReconcileCache(ctx context.Context) (*marvel.ReconcileResult, error) {
+	ret := _m.Called(ctx)
+
+	var r0 *marvel.ReconcileResult
+	if rf, ok := ret.Get(0).(func(context.Context) *marvel.ReconcileResult); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*marvel.ReconcileResult)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReloadCache provides a mock function with given fields: ctx
+func (_m *Servicer) ReloadCache(ctx context.Context) error {
+	ret := _m.Called(ctx)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func() error); ok {
-		r0 = rf()
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
 	} else {
 		r0 = ret.Error(0)
 	}
 
 	return r0
 }
+
+// Search provides a mock function with given fields: ctx, query, limit, offset
+func (_m *Servicer) Search(ctx context.Context, query string, limit int, offset int) (*marvel.SearchPage, error) {
+	ret := _m.Called(ctx, query, limit, offset)
+
+	var r0 *marvel.SearchPage
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, int) *marvel.SearchPage); ok {
+		r0 = rf(ctx, query, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*marvel.SearchPage)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, int, int) error); ok {
+		r1 = rf(ctx, query, limit, offset)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SearchCharacters provides a mock function with given fields: ctx, search
+func (_m *Servicer) SearchCharacters(ctx context.Context, search marvel.CharacterSearch) (*marvel.CharacterPage, error) {
+	ret := _m.Called(ctx, search)
+
+	var r0 *marvel.CharacterPage
+	if rf, ok := ret.Get(0).(func(context.Context, marvel.CharacterSearch) *marvel.CharacterPage); ok {
+		r0 = rf(ctx, search)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*marvel.CharacterPage)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, marvel.CharacterSearch) error); ok {
+		r1 = rf(ctx, search)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SubscribeChanges provides a mock function with given fields: lastEventId
+func (_m *Servicer) SubscribeChanges(lastEventId string) (*marvel.ChangeSubscription, error) {
+	ret := _m.Called(lastEventId)
+
+	var r0 *marvel.ChangeSubscription
+	if rf, ok := ret.Get(0).(func(string) *marvel.ChangeSubscription); ok {
+		r0 = rf(lastEventId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*marvel.ChangeSubscription)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(lastEventId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SyncCache provides a mock function with given fields: ctx
+func (_m *Servicer) SyncCache(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SyncGraph provides a mock function with given fields: ctx
+func (_m *Servicer) SyncGraph(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SyncStatus provides a mock function with given fields:
+func (_m *Servicer) SyncStatus() marvel.SyncStatus {
+	ret := _m.Called()
+
+	var r0 marvel.SyncStatus
+	if rf, ok := ret.Get(0).(func() marvel.SyncStatus); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(marvel.SyncStatus)
+	}
+
+	return r0
+}